@@ -15,18 +15,26 @@
 package digest
 
 import (
+	"context"
 	"crypto/sha256"
 	"crypto/sha512"
+	"database/sql/driver"
 	"fmt"
 	"hash"
 	"io"
+	"log/slog"
 	"regexp"
-	"sync"
+	"strings"
 )
 
 // Algorithm specifies an algorithm used to generate a digest.
+// An algorithm from a [Registry] other than the default registry references that registry,
+// unless it is one of the OCI registered algorithms, [SHA256] and [SHA512].
+// Values for the same name from different registries are therefore not equal with ==,
+// use [Algorithm.Equal] to compare by name.
 type Algorithm struct {
 	name string
+	reg  *Registry // reg is nil for the default registry
 }
 
 // algorithmInfo contains the registered data per algorithm.
@@ -38,9 +46,15 @@ type algorithmInfo struct {
 	newFn func() hash.Hash
 }
 
+// AlgorithmDetails contains the metadata for a registered [Algorithm].
+type AlgorithmDetails struct {
+	Algorithm Algorithm // Algorithm is the registered algorithm.
+	Size      int       // Size is the output byte size of the hash implementation.
+	Encoder   Encoder   // Encoder is used to generate and validate the encoded portion of a digest.
+	OCI       bool      // OCI is true for algorithms registered by the OCI image-spec, false for custom extensions.
+}
+
 var (
-	algorithms      = map[string]algorithmInfo{}
-	algorithmsMu    sync.RWMutex
 	algorithmRegexp = regexp.MustCompile(`^[a-z0-9]+([+._-][a-z0-9]+)*$`)
 	Canonical       Algorithm // Canonical is the default hashing algorithm, currently set to [SHA256].
 	SHA256          Algorithm // SHA256 defines the registered sha256 digester based on [crypto/sha256].
@@ -53,79 +67,106 @@ var (
 func init() {
 	// Ignore errors, do not panic.
 	// Predefined algorithms would be invalid if they cannot be registered for some reason.
-	aiSHA256, SHA256, _ = algorithmInfoRegister("sha256", EncodeHex{Len: 64}, sha256.New)
-	aiSHA512, SHA512, _ = algorithmInfoRegister("sha512", EncodeHex{Len: 128}, sha512.New)
+	aiSHA256, _ = newAlgorithmInfo("sha256", EncodeHex{Len: 64}, sha256.New)
+	aiSHA512, _ = newAlgorithmInfo("sha512", EncodeHex{Len: 128}, sha512.New)
+	SHA256 = Algorithm{name: aiSHA256.name}
+	SHA512 = Algorithm{name: aiSHA512.name}
 	Canonical = SHA256
 	aiCanonical = aiSHA256
 }
 
-// AlgorithmLookup is used to get a previously registered [Algorithm].
+// AlgorithmLookup is used to get a previously registered [Algorithm] from the default [Registry].
 func AlgorithmLookup(name string) (Algorithm, error) {
-	_, a, err := algorithmInfoLookup(name)
-	return a, err
+	return defaultRegistry.Lookup(name)
 }
 
-func algorithmInfoLookup(name string) (algorithmInfo, Algorithm, error) {
-	// skip the lock for registered algorithms
-	switch name {
-	case "sha256":
-		return aiSHA256, SHA256, nil
-	case "sha512":
-		return aiSHA512, SHA512, nil
-	case "":
-		return algorithmInfo{}, Algorithm{}, ErrAlgorithmInvalidName
-	}
-
-	algorithmsMu.RLock()
-	defer algorithmsMu.RUnlock()
-
-	if a, ok := algorithms[name]; ok {
-		return a, Algorithm{name: a.name}, nil
-	}
-	return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrAlgorithmUnknown, name)
+// AlgorithmList returns the algorithms in the default [Registry], sorted by name.
+func AlgorithmList() []Algorithm {
+	return defaultRegistry.List()
 }
 
-// AlgorithmRegister is used to register new hash algorithms.
+// AlgorithmRegister is used to register new hash algorithms in the default [Registry].
 // Attempting to register an already registered algorithm will fail.
 // The name must follow the regexp "[a-z0-9]+([+._-][a-z0-9]+)*".
 // The encoder and hash function are also verified to be valid interfaces.
 func AlgorithmRegister(name string, enc Encoder, newFn func() hash.Hash) (Algorithm, error) {
-	_, a, err := algorithmInfoRegister(name, enc, newFn)
-	return a, err
+	return defaultRegistry.Register(name, enc, newFn)
 }
 
-func algorithmInfoRegister(name string, enc Encoder, newFn func() hash.Hash) (algorithmInfo, Algorithm, error) {
-	algorithmsMu.Lock()
-	defer algorithmsMu.Unlock()
-
-	if _, ok := algorithms[name]; ok {
-		return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrAlgorithmExists, name)
-	}
+// newAlgorithmInfo validates the inputs to register an algorithm.
+func newAlgorithmInfo(name string, enc Encoder, newFn func() hash.Hash) (algorithmInfo, error) {
 	if !algorithmRegexp.MatchString(name) {
-		return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrAlgorithmInvalidName, name)
+		return algorithmInfo{}, fmt.Errorf("%w: %s", ErrAlgorithmInvalidName, name)
 	}
 	if enc == nil {
-		return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrEncodeInterfaceInvalid, name)
+		return algorithmInfo{}, fmt.Errorf("%w: %s", ErrEncodeInterfaceInvalid, name)
 	}
 	if newFn == nil {
-		return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrHashFunctionInvalid, name)
+		return algorithmInfo{}, fmt.Errorf("%w: %s", ErrHashFunctionInvalid, name)
 	}
 	hasher := newFn()
 	if hasher == nil {
-		return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrHashFunctionInvalid, name)
+		return algorithmInfo{}, fmt.Errorf("%w: %s", ErrHashFunctionInvalid, name)
 	}
 	size := hasher.Size()
 	if size <= 0 {
-		return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrHashFunctionInvalid, name)
+		return algorithmInfo{}, fmt.Errorf("%w: %s", ErrHashFunctionInvalid, name)
 	}
-	alg := algorithmInfo{
+	return algorithmInfo{
 		name:  name,
 		size:  size,
 		enc:   enc,
 		newFn: newFn,
+	}, nil
+}
+
+// info returns the registered data for the algorithm from the associated registry.
+func (a Algorithm) info() (algorithmInfo, error) {
+	ai, _, err := a.registry().infoLookup(a.name)
+	return ai, err
+}
+
+// registry returns the registry associated with the algorithm.
+func (a Algorithm) registry() *Registry {
+	if a.reg == nil {
+		return defaultRegistry
+	}
+	return a.reg
+}
+
+// Compare returns an integer comparing two algorithms by name, compatible with [slices.SortFunc].
+// The result is 0 if a == cmp, -1 if a < cmp, and +1 if a > cmp.
+func (a Algorithm) Compare(cmp Algorithm) int {
+	return strings.Compare(a.name, cmp.name)
+}
+
+// Decode converts the encoded string for a digest back to the byte slice hash sum.
+// This will fail if the encoder for the algorithm does not implement [Decoder].
+func (a Algorithm) Decode(s string) ([]byte, error) {
+	ai, err := a.info()
+	if err != nil {
+		return nil, err
+	}
+	dec, ok := ai.enc.(Decoder)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDecodeUnsupported, a.name)
+	}
+	return dec.Decode(s)
+}
+
+// Details returns the metadata for the algorithm.
+// This will fail if the algorithm is not registered.
+func (a Algorithm) Details() (AlgorithmDetails, error) {
+	ai, err := a.info()
+	if err != nil {
+		return AlgorithmDetails{}, err
 	}
-	algorithms[name] = alg
-	return alg, Algorithm{name: name}, nil
+	return AlgorithmDetails{
+		Algorithm: a,
+		Size:      ai.size,
+		Encoder:   ai.enc,
+		OCI:       a.name == SHA256.name || a.name == SHA512.name,
+	}, nil
 }
 
 // Digester creates a new [Digester] for the algorithm.
@@ -138,14 +179,14 @@ func (a Algorithm) Digester() (Digester, error) {
 
 // Encode converts the byte slice hash sum to an encoded string for a digest.
 func (a Algorithm) Encode(p []byte) (string, error) {
-	ai, _, err := algorithmInfoLookup(a.name)
+	ai, err := a.info()
 	if err != nil {
 		return "", err
 	}
 	return ai.enc.Encode(p)
 }
 
-// Equal returns true if the algorithms are the same.
+// Equal returns true if the algorithms have the same name.
 func (a Algorithm) Equal(cmp Algorithm) bool {
 	return a.name == cmp.name
 }
@@ -176,6 +217,21 @@ func (a Algorithm) FromReader(rd io.Reader) (Digest, error) {
 	return dr.Digest()
 }
 
+// FromReaderContext generates a digest on the input reader using the algorithm and returns a [Digest].
+// The context is checked between each chunk read, returning a [*PartialError] with the number of bytes read when the context is done.
+// A single blocked read is not interrupted, so the reader should also honor the context, e.g. an http request created with the context.
+// This will fail if the algorithm is invalid, on read errors, or when the context is done.
+func (a Algorithm) FromReaderContext(ctx context.Context, rd io.Reader) (Digest, error) {
+	dr, err := a.Digester()
+	if err != nil {
+		return Digest{}, err
+	}
+	if _, err := copyContext(ctx, dr, rd); err != nil {
+		return Digest{}, err
+	}
+	return dr.Digest()
+}
+
 // FromString generates a digest on the input string using the algorithm and returns a [Digest].
 // This will fail if the algorithm is invalid.
 func (a Algorithm) FromString(s string) (Digest, error) {
@@ -185,7 +241,7 @@ func (a Algorithm) FromString(s string) (Digest, error) {
 // Hash returns a new [hash.Hash] for the algorithm.
 // nil is returned if the algorithm is invalid.
 func (a Algorithm) Hash() hash.Hash {
-	ai, _, err := algorithmInfoLookup(a.name)
+	ai, err := a.info()
 	if err != nil {
 		return nil
 	}
@@ -197,9 +253,37 @@ func (a Algorithm) IsZero() bool {
 	return a.name == ""
 }
 
+// LogValue implements [slog.LogValuer], returning the algorithm name.
+func (a Algorithm) LogValue() slog.Value {
+	return slog.StringValue(a.name)
+}
+
+// Scan implements [database/sql.Scanner], looking up the algorithm name in the default [Registry].
+// A NULL value is scanned as the zero value.
+func (a *Algorithm) Scan(src any) error {
+	var name string
+	switch v := src.(type) {
+	case nil:
+		*a = Algorithm{}
+		return nil
+	case string:
+		name = v
+	case []byte:
+		name = string(v)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrAlgorithmInvalidName, src)
+	}
+	newA, err := AlgorithmLookup(name)
+	if err != nil {
+		return err
+	}
+	*a = newA
+	return nil
+}
+
 // Size returns the detected output byte size of the hash implementation.
 func (a Algorithm) Size() int {
-	ai, _, _ := algorithmInfoLookup(a.name)
+	ai, _ := a.info()
 	return ai.size
 }
 
@@ -207,3 +291,12 @@ func (a Algorithm) Size() int {
 func (a Algorithm) String() string {
 	return a.name
 }
+
+// Value implements [database/sql/driver.Valuer], returning the algorithm name.
+// The zero value returns NULL.
+func (a Algorithm) Value() (driver.Value, error) {
+	if a.name == "" {
+		return nil, nil
+	}
+	return a.name, nil
+}
@@ -15,6 +15,8 @@
 package digest
 
 import (
+	"bytes"
+	"context"
 	"crypto/sha256"
 	"crypto/sha512"
 	"errors"
@@ -86,6 +88,11 @@ func TestAlgorithmRegister(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected err: %v", err)
 			}
+			t.Cleanup(func() {
+				if err := DefaultRegistry().Unregister(tc.alg); err != nil {
+					t.Errorf("failed to unregister: %v", err)
+				}
+			})
 			if a.name != tc.alg {
 				t.Errorf("name mismatch, expected %s, received %s", tc.alg, a.name)
 			}
@@ -93,6 +100,77 @@ func TestAlgorithmRegister(t *testing.T) {
 	}
 }
 
+func TestAlgorithmList(t *testing.T) {
+	list := AlgorithmList()
+	found := map[string]bool{}
+	for i, a := range list {
+		found[a.name] = true
+		if i > 0 && list[i-1].name >= a.name {
+			t.Errorf("list is not sorted: %s, %s", list[i-1].name, a.name)
+		}
+	}
+	for _, name := range []string{"sha256", "sha512"} {
+		if !found[name] {
+			t.Errorf("missing algorithm %s", name)
+		}
+	}
+}
+
+func TestAlgorithmDetails(t *testing.T) {
+	custom, err := NewRegistry().Register("details-test", EncodeHex{Len: 96}, sha512.New384)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	tt := []struct {
+		name   string
+		a      Algorithm
+		expect AlgorithmDetails
+		err    error
+	}{
+		{
+			name: "uninitialized",
+			err:  ErrAlgorithmInvalidName,
+		},
+		{
+			name: "unknown",
+			a:    Algorithm{name: "unknown"},
+			err:  ErrAlgorithmUnknown,
+		},
+		{
+			name:   "sha256",
+			a:      SHA256,
+			expect: AlgorithmDetails{Algorithm: SHA256, Size: 32, Encoder: EncodeHex{Len: 64}, OCI: true},
+		},
+		{
+			name:   "sha512",
+			a:      SHA512,
+			expect: AlgorithmDetails{Algorithm: SHA512, Size: 64, Encoder: EncodeHex{Len: 128}, OCI: true},
+		},
+		{
+			name:   "custom",
+			a:      custom,
+			expect: AlgorithmDetails{Algorithm: custom, Size: 48, Encoder: EncodeHex{Len: 96}, OCI: false},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := tc.a.Details()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if d != tc.expect {
+				t.Errorf("expected %v, received %v", tc.expect, d)
+			}
+		})
+	}
+}
+
 func TestAlgorithmLookup(t *testing.T) {
 	tt := []struct {
 		name string
@@ -439,3 +517,145 @@ func TestAlgorithmString(t *testing.T) {
 		})
 	}
 }
+
+func TestAlgorithmSQL(t *testing.T) {
+	tt := []struct {
+		name   string
+		src    any
+		expect Algorithm
+		err    error
+	}{
+		{
+			name: "null",
+		},
+		{
+			name:   "string",
+			src:    "sha512",
+			expect: SHA512,
+		},
+		{
+			name:   "bytes",
+			src:    []byte("sha256"),
+			expect: SHA256,
+		},
+		{
+			name: "unknown",
+			src:  "unknown",
+			err:  ErrAlgorithmUnknown,
+		},
+		{
+			name: "unsupported-type",
+			src:  42,
+			err:  ErrAlgorithmInvalidName,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Algorithm{name: "previous"}
+			err := a.Scan(tc.src)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !a.Equal(tc.expect) {
+				t.Errorf("expected %s, received %s", tc.expect.String(), a.String())
+			}
+			v, err := a.Value()
+			if err != nil {
+				t.Fatalf("unexpected value err: %v", err)
+			}
+			if tc.expect.IsZero() {
+				if v != nil {
+					t.Errorf("expected nil value, received %v", v)
+				}
+			} else if v != tc.expect.String() {
+				t.Errorf("expected value %s, received %v", tc.expect.String(), v)
+			}
+		})
+	}
+}
+
+func TestAlgorithmCompare(t *testing.T) {
+	tt := []struct {
+		name   string
+		a, b   Algorithm
+		expect int
+	}{
+		{
+			name: "zero",
+		},
+		{
+			name: "same",
+			a:    SHA256,
+			b:    SHA256,
+		},
+		{
+			name:   "less",
+			a:      SHA256,
+			b:      SHA512,
+			expect: -1,
+		},
+		{
+			name:   "greater",
+			a:      SHA512,
+			b:      SHA256,
+			expect: 1,
+		},
+		{
+			name:   "zero-first",
+			b:      SHA256,
+			expect: -1,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.a.Compare(tc.b)
+			if c != tc.expect {
+				t.Errorf("expected %d, received %d", tc.expect, c)
+			}
+		})
+	}
+}
+
+func TestAlgorithmFromReaderContext(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	for _, alg := range []Algorithm{SHA256, SHA512} {
+		t.Run(alg.String(), func(t *testing.T) {
+			expect, err := alg.FromBytes(data)
+			if err != nil {
+				t.Fatalf("failed to digest: %v", err)
+			}
+			d, err := alg.FromReaderContext(context.Background(), bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("failed to digest: %v", err)
+			}
+			if !d.Equal(expect) {
+				t.Errorf("expected %s, received %s", expect.String(), d.String())
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cr := &cancelReader{r: bytes.NewReader(data), chunk: 100, after: 5, cancel: cancel}
+			d, err = alg.FromReaderContext(ctx, cr)
+			var pErr *PartialError
+			if !errors.As(err, &pErr) || pErr.Size != 500 || !errors.Is(err, context.Canceled) {
+				t.Errorf("expected partial error with 500 bytes, received %v", err)
+			}
+			if !d.IsZero() {
+				t.Errorf("expected zero digest, received %s", d.String())
+			}
+		})
+	}
+	d, err := FromReaderContext(context.Background(), bytes.NewReader(data))
+	if err != nil || d.Algorithm() != Canonical {
+		t.Errorf("unexpected canonical digest: %s, %v", d.String(), err)
+	}
+	_, err = Algorithm{}.FromReaderContext(context.Background(), bytes.NewReader(data))
+	if err == nil {
+		t.Errorf("expected error on zero algorithm")
+	}
+}
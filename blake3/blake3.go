@@ -0,0 +1,40 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blake3 registers the blake3 algorithm with the digest package.
+// Importing this package registers "blake3" with a 256 bit output using a pure Go implementation of [BLAKE3].
+//
+// The blake3 algorithm is not registered by the OCI image-spec and may not be supported by other implementations.
+//
+// [BLAKE3]: https://github.com/BLAKE3-team/BLAKE3-specs
+package blake3
+
+import (
+	"hash"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+// BLAKE3 defines the registered blake3 digester based on [New].
+var BLAKE3 digest.Algorithm
+
+func init() {
+	// Ignore errors, do not panic.
+	// An algorithm already registered with the same name is returned instead.
+	alg, err := digest.AlgorithmRegister("blake3", digest.EncodeHex{Len: Size * 2}, func() hash.Hash { return New() })
+	if err != nil {
+		alg, _ = digest.AlgorithmLookup("blake3")
+	}
+	BLAKE3 = alg
+}
@@ -0,0 +1,265 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blake3
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	// Size is the default output size of BLAKE3 in bytes.
+	Size = 32
+	// BlockSize is the block size of BLAKE3 in bytes.
+	BlockSize = 64
+
+	chunkLen       = 1024
+	flagChunkStart = 1 << 0
+	flagChunkEnd   = 1 << 1
+	flagParent     = 1 << 2
+	flagRoot       = 1 << 3
+)
+
+var (
+	iv = [8]uint32{
+		0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+		0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+	}
+	msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+)
+
+// Hasher computes the BLAKE3 hash in the default hashing mode.
+// It implements [hash.Hash].
+type Hasher struct {
+	chunk  chunkState
+	key    [8]uint32
+	stack  [54][8]uint32
+	stackN int
+	flags  uint32
+}
+
+var _ hash.Hash = (*Hasher)(nil)
+
+// New returns a new [Hasher] computing the 256 bit BLAKE3 hash.
+func New() *Hasher {
+	h := &Hasher{key: iv}
+	h.Reset()
+	return h
+}
+
+// BlockSize returns the block size of the hash.
+func (h *Hasher) BlockSize() int {
+	return BlockSize
+}
+
+// Reset resets the hash to its initial state.
+func (h *Hasher) Reset() {
+	h.chunk = newChunkState(h.key, 0, h.flags)
+	h.stackN = 0
+}
+
+// Size returns the number of bytes returned by Sum.
+func (h *Hasher) Size() int {
+	return Size
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (h *Hasher) Sum(b []byte) []byte {
+	out := make([]byte, Size)
+	h.sum(out)
+	return append(b, out...)
+}
+
+// Write adds more data to the running hash.
+// It never returns an error.
+func (h *Hasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if h.chunk.len() == chunkLen {
+			cv := h.chunk.output().chainingValue()
+			total := h.chunk.counter + 1
+			h.addChunkChainingValue(cv, total)
+			h.chunk = newChunkState(h.key, total, h.flags)
+		}
+		want := chunkLen - h.chunk.len()
+		take := min(want, len(p))
+		h.chunk.update(p[:take])
+		p = p[take:]
+	}
+	return n, nil
+}
+
+// sum fills out with the extended output of the hash.
+func (h *Hasher) sum(out []byte) {
+	o := h.chunk.output()
+	for i := h.stackN - 1; i >= 0; i-- {
+		o = parentOutput(h.stack[i], o.chainingValue(), h.key, h.flags)
+	}
+	o.rootBytes(out)
+}
+
+func (h *Hasher) addChunkChainingValue(cv [8]uint32, total uint64) {
+	// merge completed subtrees, one for each trailing zero bit in the chunk count
+	for total&1 == 0 {
+		h.stackN--
+		cv = parentOutput(h.stack[h.stackN], cv, h.key, h.flags).chainingValue()
+		total >>= 1
+	}
+	h.stack[h.stackN] = cv
+	h.stackN++
+}
+
+type chunkState struct {
+	cv               [8]uint32
+	counter          uint64
+	block            [BlockSize]byte
+	blockLen         int
+	blocksCompressed int
+	flags            uint32
+}
+
+func newChunkState(key [8]uint32, counter uint64, flags uint32) chunkState {
+	return chunkState{
+		cv:      key,
+		counter: counter,
+		flags:   flags,
+	}
+}
+
+func (c *chunkState) len() int {
+	return BlockSize*c.blocksCompressed + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocksCompressed == 0 {
+		return flagChunkStart
+	}
+	return 0
+}
+
+func (c *chunkState) update(p []byte) {
+	for len(p) > 0 {
+		if c.blockLen == BlockSize {
+			words := blockWords(c.block[:])
+			out := compress(c.cv, words, c.counter, BlockSize, c.flags|c.startFlag())
+			copy(c.cv[:], out[:8])
+			c.blocksCompressed++
+			c.block = [BlockSize]byte{}
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		p = p[n:]
+	}
+}
+
+func (c *chunkState) output() output {
+	return output{
+		cv:       c.cv,
+		block:    blockWords(c.block[:]),
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.flags | c.startFlag() | flagChunkEnd,
+	}
+}
+
+type output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func parentOutput(left, right, key [8]uint32, flags uint32) output {
+	o := output{
+		cv:       key,
+		blockLen: BlockSize,
+		flags:    flags | flagParent,
+	}
+	copy(o.block[:8], left[:])
+	copy(o.block[8:], right[:])
+	return o
+}
+
+func (o output) chainingValue() [8]uint32 {
+	var cv [8]uint32
+	out := compress(o.cv, o.block, o.counter, o.blockLen, o.flags)
+	copy(cv[:], out[:8])
+	return cv
+}
+
+func (o output) rootBytes(p []byte) {
+	var buf [BlockSize]byte
+	for counter := uint64(0); len(p) > 0; counter++ {
+		words := compress(o.cv, o.block, counter, o.blockLen, o.flags|flagRoot)
+		for i, w := range words {
+			binary.LittleEndian.PutUint32(buf[i*4:], w)
+		}
+		n := copy(p, buf[:])
+		p = p[n:]
+	}
+}
+
+func blockWords(b []byte) [16]uint32 {
+	var words [16]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	return words
+}
+
+func compress(cv [8]uint32, m [16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	for r := 0; r < 7; r++ {
+		g(&s, 0, 4, 8, 12, m[0], m[1])
+		g(&s, 1, 5, 9, 13, m[2], m[3])
+		g(&s, 2, 6, 10, 14, m[4], m[5])
+		g(&s, 3, 7, 11, 15, m[6], m[7])
+		g(&s, 0, 5, 10, 15, m[8], m[9])
+		g(&s, 1, 6, 11, 12, m[10], m[11])
+		g(&s, 2, 7, 8, 13, m[12], m[13])
+		g(&s, 3, 4, 9, 14, m[14], m[15])
+		if r < 6 {
+			var p [16]uint32
+			for i, j := range msgPermutation {
+				p[i] = m[j]
+			}
+			m = p
+		}
+	}
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+	return s
+}
+
+func g(s *[16]uint32, a, b, c, d int, mx, my uint32) {
+	s[a] = s[a] + s[b] + mx
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] = s[c] + s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] = s[a] + s[b] + my
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] = s[c] + s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
@@ -0,0 +1,237 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blobstore implements a content addressable blob store on the local filesystem.
+// Blobs are stored using the OCI [image-layout] directory structure, "blobs/<alg>/<encoded>",
+// allowing the root of the store to be an OCI layout.
+//
+// [image-layout]: https://github.com/opencontainers/image-spec/blob/v1.1.1/image-layout.md
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+const blobsDir = "blobs"
+
+// Store is a content addressable blob store in a directory.
+type Store struct {
+	root string
+	alg  digest.Algorithm
+}
+
+// New creates a [Store] in the root directory.
+// Directories are created when the first blob is added.
+// If Algorithm is the zero value, the [digest.Canonical] value will be used for blobs added without an expected digest.
+func New(root string, alg digest.Algorithm) *Store {
+	if alg.IsZero() {
+		alg = digest.Canonical
+	}
+	return &Store{
+		root: root,
+		alg:  alg,
+	}
+}
+
+// Delete removes a blob from the store.
+func (s *Store) Delete(d digest.Digest) error {
+	p, err := s.path(d)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		return notFound(err)
+	}
+	return nil
+}
+
+// Get returns a reader for a blob in the store.
+// The returned reader is a [digest.Verifier] and will fail on the final read if the content does not match the digest.
+// The caller must close the returned reader.
+func (s *Store) Get(d digest.Digest) (io.ReadCloser, error) {
+	p, err := s.path(d)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return blobReader{
+		Verifier: digest.NewVerifier(f, d),
+		Closer:   f,
+	}, nil
+}
+
+// Put adds the content from the reader to the store, returning the digest and size of the content.
+// If the expected digest is provided, the algorithm of that digest is used and
+// the content is rejected with a [digest.MismatchError] if the computed digest differs.
+// Content is written to a temporary file, synced to disk, and renamed into place after the digest has been computed.
+func (s *Store) Put(r io.Reader, expect digest.Digest) (digest.Digest, int64, error) {
+	alg := s.alg
+	if !expect.IsZero() {
+		alg = expect.Algorithm()
+		if _, err := alg.Details(); err != nil {
+			return digest.Digest{}, 0, err
+		}
+	}
+	dir := filepath.Join(s.root, blobsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return digest.Digest{}, 0, err
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return digest.Digest{}, 0, err
+	}
+	tmpName := tmp.Name()
+	// cleanup the temp file on any failure, this is a noop after the rename
+	defer os.Remove(tmpName)
+
+	w := digest.NewWriter(tmp, alg)
+	n, err := io.Copy(w, r)
+	if err != nil {
+		_ = tmp.Close()
+		return digest.Digest{}, 0, err
+	}
+	// flush the content to disk before the rename so a crash cannot leave a truncated blob under the digest name
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return digest.Digest{}, 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return digest.Digest{}, 0, err
+	}
+	d, err := w.Digest()
+	if err != nil {
+		return digest.Digest{}, 0, err
+	}
+	if !expect.IsZero() && !d.EqualConstantTime(expect) {
+		return digest.Digest{}, 0, &digest.MismatchError{Expected: expect, Actual: d}
+	}
+	p, err := s.path(d)
+	if err != nil {
+		return digest.Digest{}, 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return digest.Digest{}, 0, err
+	}
+	if err := os.Rename(tmpName, p); err != nil {
+		return digest.Digest{}, 0, err
+	}
+	if err := syncDir(filepath.Dir(p)); err != nil {
+		return digest.Digest{}, 0, err
+	}
+	return d, n, nil
+}
+
+// Stat returns the size of a blob in the store.
+func (s *Store) Stat(d digest.Digest) (int64, error) {
+	p, err := s.path(d)
+	if err != nil {
+		return 0, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return 0, notFound(err)
+	}
+	return fi.Size(), nil
+}
+
+// Walk calls fn for every blob in the store with the digest and size of the blob.
+// Files that do not have a valid digest for a registered algorithm are skipped.
+// Walk stops and returns the error when fn returns an error.
+func (s *Store) Walk(fn func(d digest.Digest, size int64) error) error {
+	dir := filepath.Join(s.root, blobsDir)
+	algDirs, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, algDir := range algDirs {
+		if !algDir.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(dir, algDir.Name()))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			d, err := digest.Parse(algDir.Name() + ":" + entry.Name())
+			if err != nil {
+				continue
+			}
+			fi, err := entry.Info()
+			if errors.Is(err, fs.ErrNotExist) {
+				// deleted during the walk
+				continue
+			} else if err != nil {
+				return err
+			}
+			if err := fn(d, fi.Size()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// path returns the filename for a digest.
+// The digest must match the OCI character set to avoid path traversal from custom encoders.
+func (s *Store) path(d digest.Digest) (string, error) {
+	if !digest.DigestRegexpAnchored.MatchString(d.String()) {
+		return "", digest.ErrDigestInvalid
+	}
+	return filepath.Join(s.root, blobsDir, d.Algorithm().String(), d.Encoded()), nil
+}
+
+type blobReader struct {
+	*digest.Verifier
+	io.Closer
+}
+
+func notFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", digest.ErrDigestNotFound, err)
+	}
+	return err
+}
+
+// syncDir flushes the directory entries to disk, persisting a rename.
+// Directories cannot be synced on Windows, so this is skipped.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+func TestStore(t *testing.T) {
+	root := t.TempDir()
+	s := New(root, digest.Algorithm{})
+	content := []byte("{}")
+	emptyJSON, err := digest.Parse("sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a")
+	if err != nil {
+		t.Fatalf("failed to parse digest: %v", err)
+	}
+	emptyJSON512, err := digest.SHA512.FromBytes(content)
+	if err != nil {
+		t.Fatalf("failed to generate digest: %v", err)
+	}
+	missing, err := digest.FromString("missing")
+	if err != nil {
+		t.Fatalf("failed to generate digest: %v", err)
+	}
+
+	t.Run("empty-walk", func(t *testing.T) {
+		err := s.Walk(func(d digest.Digest, size int64) error {
+			t.Errorf("unexpected blob %s", d.String())
+			return nil
+		})
+		if err != nil {
+			t.Errorf("unexpected err: %v", err)
+		}
+	})
+	t.Run("put", func(t *testing.T) {
+		d, n, err := s.Put(bytes.NewReader(content), digest.Digest{})
+		if err != nil {
+			t.Fatalf("failed to put: %v", err)
+		}
+		if !d.Equal(emptyJSON) || n != int64(len(content)) {
+			t.Errorf("unexpected put result %s, %d", d.String(), n)
+		}
+		// layout path
+		b, err := os.ReadFile(filepath.Join(root, "blobs", "sha256", emptyJSON.Encoded()))
+		if err != nil {
+			t.Fatalf("failed to read blob: %v", err)
+		}
+		if !bytes.Equal(b, content) {
+			t.Errorf("unexpected blob content %s", b)
+		}
+		// repeated put
+		if _, _, err := s.Put(bytes.NewReader(content), emptyJSON); err != nil {
+			t.Errorf("failed to put existing blob: %v", err)
+		}
+	})
+	t.Run("put-expected-algorithm", func(t *testing.T) {
+		d, _, err := s.Put(bytes.NewReader(content), emptyJSON512)
+		if err != nil {
+			t.Fatalf("failed to put: %v", err)
+		}
+		if !d.Equal(emptyJSON512) {
+			t.Errorf("expected %s, received %s", emptyJSON512.String(), d.String())
+		}
+	})
+	t.Run("put-mismatch", func(t *testing.T) {
+		_, _, err := s.Put(strings.NewReader("[]"), emptyJSON)
+		if !errors.Is(err, digest.ErrDigestMismatch) {
+			t.Errorf("expected err %v, received %v", digest.ErrDigestMismatch, err)
+		}
+		// temp files are removed
+		entries, err := os.ReadDir(filepath.Join(root, "blobs"))
+		if err != nil {
+			t.Fatalf("failed to read dir: %v", err)
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".tmp-") {
+				t.Errorf("temp file not removed: %s", e.Name())
+			}
+		}
+	})
+	t.Run("get", func(t *testing.T) {
+		rc, err := s.Get(emptyJSON)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if !bytes.Equal(b, content) {
+			t.Errorf("unexpected content %s", b)
+		}
+	})
+	t.Run("get-missing", func(t *testing.T) {
+		_, err := s.Get(missing)
+		if !errors.Is(err, digest.ErrDigestNotFound) || !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected not found, received %v", err)
+		}
+	})
+	t.Run("get-corrupt", func(t *testing.T) {
+		d, _, err := s.Put(strings.NewReader("corrupt"), digest.Digest{})
+		if err != nil {
+			t.Fatalf("failed to put: %v", err)
+		}
+		err = os.WriteFile(filepath.Join(root, "blobs", "sha256", d.Encoded()), []byte("modified"), 0o644)
+		if err != nil {
+			t.Fatalf("failed to modify blob: %v", err)
+		}
+		rc, err := s.Get(d)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		defer rc.Close()
+		if _, err := io.ReadAll(rc); !errors.Is(err, digest.ErrDigestMismatch) {
+			t.Errorf("expected err %v, received %v", digest.ErrDigestMismatch, err)
+		}
+		if err := s.Delete(d); err != nil {
+			t.Errorf("failed to delete: %v", err)
+		}
+	})
+	t.Run("stat", func(t *testing.T) {
+		n, err := s.Stat(emptyJSON)
+		if err != nil {
+			t.Fatalf("failed to stat: %v", err)
+		}
+		if n != int64(len(content)) {
+			t.Errorf("expected size %d, received %d", len(content), n)
+		}
+		if _, err := s.Stat(missing); !errors.Is(err, digest.ErrDigestNotFound) {
+			t.Errorf("expected err %v, received %v", digest.ErrDigestNotFound, err)
+		}
+		if _, err := s.Stat(digest.Digest{}); !errors.Is(err, digest.ErrDigestInvalid) {
+			t.Errorf("expected err %v, received %v", digest.ErrDigestInvalid, err)
+		}
+	})
+	t.Run("walk", func(t *testing.T) {
+		// unrelated files are skipped
+		if err := os.WriteFile(filepath.Join(root, "blobs", "sha256", "invalid"), []byte("x"), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		found := []digest.Digest{}
+		err := s.Walk(func(d digest.Digest, size int64) error {
+			found = append(found, d)
+			if size != int64(len(content)) {
+				t.Errorf("unexpected size %d for %s", size, d.String())
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to walk: %v", err)
+		}
+		digest.Sort(found)
+		if len(found) != 2 || !found[0].Equal(emptyJSON) || !found[1].Equal(emptyJSON512) {
+			t.Errorf("unexpected walk results: %v", found)
+		}
+		errStop := errors.New("stop")
+		if err := s.Walk(func(d digest.Digest, size int64) error { return errStop }); !errors.Is(err, errStop) {
+			t.Errorf("expected err %v, received %v", errStop, err)
+		}
+	})
+	t.Run("delete", func(t *testing.T) {
+		if err := s.Delete(emptyJSON); err != nil {
+			t.Fatalf("failed to delete: %v", err)
+		}
+		if err := s.Delete(emptyJSON); !errors.Is(err, digest.ErrDigestNotFound) {
+			t.Errorf("expected err %v, received %v", digest.ErrDigestNotFound, err)
+		}
+		if _, err := s.Stat(emptyJSON); !errors.Is(err, digest.ErrDigestNotFound) {
+			t.Errorf("expected err %v, received %v", digest.ErrDigestNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command oci-digest computes and checks OCI digests of files.
+//
+// Usage:
+//
+//	oci-digest [flags] [file ...]
+//	oci-digest -check [flags] [manifest ...]
+//
+// With no file, or when file is "-", the content is read from stdin.
+// The output is either "<alg>:<encoded>  <file>" or, with "-format sum", the sha256sum compatible "<encoded>  <file>".
+// In check mode, each manifest contains lines in either output format.
+// Each file is reported as OK or FAILED, and the exit code is non-zero if any file fails.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+	_ "github.com/sudo-bmitch/oci-digest/blake3"
+	_ "github.com/sudo-bmitch/oci-digest/sha2"
+)
+
+const (
+	formatDigest = "digest"
+	formatSum    = "sum"
+)
+
+type options struct {
+	alg    digest.Algorithm
+	check  bool
+	format string
+	quiet  bool
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command, returning the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := options{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	names := []string{}
+	for _, a := range digest.AlgorithmList() {
+		names = append(names, a.String())
+	}
+	fs := flag.NewFlagSet("oci-digest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	algName := fs.String("algorithm", digest.Canonical.String(), "digest algorithm, one of: "+strings.Join(names, ", "))
+	fs.StringVar(algName, "a", digest.Canonical.String(), "shorthand for -algorithm")
+	fs.BoolVar(&opts.check, "check", false, "read digests from the manifest files and check them")
+	fs.BoolVar(&opts.check, "c", false, "shorthand for -check")
+	fs.StringVar(&opts.format, "format", formatDigest, `output format, "digest" for "<alg>:<encoded>" or "sum" for sha256sum compatible output`)
+	fs.BoolVar(&opts.quiet, "quiet", false, "in check mode, do not print OK for each successfully verified file")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: oci-digest [flags] [file ...]\n       oci-digest -check [flags] [manifest ...]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	alg, err := digest.AlgorithmLookup(*algName)
+	if err != nil {
+		fmt.Fprintf(stderr, "oci-digest: %v\n", err)
+		return 2
+	}
+	opts.alg = alg
+	if opts.format != formatDigest && opts.format != formatSum {
+		fmt.Fprintf(stderr, "oci-digest: unknown format %q\n", opts.format)
+		return 2
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if opts.check {
+		return checkFiles(opts, files)
+	}
+	return digestFiles(opts, files)
+}
+
+// digestFiles outputs the digest of each file.
+func digestFiles(opts options, files []string) int {
+	rc := 0
+	for _, file := range files {
+		d, err := digestFile(opts, opts.alg, file)
+		if err != nil {
+			fmt.Fprintf(opts.stderr, "oci-digest: %s: %v\n", file, err)
+			rc = 1
+			continue
+		}
+		switch opts.format {
+		case formatSum:
+			fmt.Fprintf(opts.stdout, "%s  %s\n", d.Encoded(), file)
+		default:
+			fmt.Fprintf(opts.stdout, "%s  %s\n", d.String(), file)
+		}
+	}
+	return rc
+}
+
+// checkFiles verifies the digests listed in each manifest.
+// A manifest that cannot be read is reported and the remaining manifests are still checked.
+func checkFiles(opts options, manifests []string) int {
+	failed, invalid, unreadable := 0, 0, 0
+	for _, manifest := range manifests {
+		err := openFile(opts, manifest, func(r io.Reader) error {
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				line := scanner.Text()
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				expect, file, err := parseLine(opts, line)
+				if err != nil {
+					invalid++
+					fmt.Fprintf(opts.stderr, "oci-digest: %s: %v\n", manifest, err)
+					continue
+				}
+				d, err := digestFile(opts, expect.Algorithm(), file)
+				if err != nil {
+					failed++
+					fmt.Fprintf(opts.stdout, "%s: FAILED open or read\n", file)
+					fmt.Fprintf(opts.stderr, "oci-digest: %s: %v\n", file, err)
+					continue
+				}
+				if !d.EqualConstantTime(expect) {
+					failed++
+					fmt.Fprintf(opts.stdout, "%s: FAILED\n", file)
+					continue
+				}
+				if !opts.quiet {
+					fmt.Fprintf(opts.stdout, "%s: OK\n", file)
+				}
+			}
+			return scanner.Err()
+		})
+		if err != nil {
+			unreadable++
+			fmt.Fprintf(opts.stderr, "oci-digest: %s: %v\n", manifest, err)
+			continue
+		}
+	}
+	if invalid > 0 {
+		fmt.Fprintf(opts.stderr, "oci-digest: WARNING: %d line(s) are improperly formatted\n", invalid)
+	}
+	if failed > 0 {
+		fmt.Fprintf(opts.stderr, "oci-digest: WARNING: %d computed digest(s) did NOT match\n", failed)
+		return 1
+	}
+	if invalid > 0 || unreadable > 0 {
+		return 1
+	}
+	return 0
+}
+
+// parseLine parses a manifest line in either the digest or sum format.
+// The sum format uses the algorithm from the options.
+// A trailing carriage return from CRLF line endings is ignored.
+func parseLine(opts options, line string) (digest.Digest, string, error) {
+	line = strings.TrimSuffix(line, "\r")
+	dStr, file, ok := strings.Cut(line, " ")
+	if !ok || len(file) < 2 || (file[0] != ' ' && file[0] != '*') {
+		return digest.Digest{}, "", fmt.Errorf("invalid line: %s", line)
+	}
+	file = file[1:]
+	if strings.Contains(dStr, ":") {
+		d, err := digest.Parse(dStr)
+		return d, file, err
+	}
+	d, err := digest.NewDigestFromEncoded(opts.alg, dStr)
+	return d, file, err
+}
+
+// digestFile computes the digest of a file, or stdin when the file is "-".
+func digestFile(opts options, alg digest.Algorithm, file string) (digest.Digest, error) {
+	var d digest.Digest
+	err := openFile(opts, file, func(r io.Reader) error {
+		var err error
+		d, err = alg.FromReader(r)
+		return err
+	})
+	return d, err
+}
+
+func openFile(opts options, file string, fn func(io.Reader) error) error {
+	if file == "-" {
+		return fn(opts.stdin)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return fn(f)
+}
@@ -0,0 +1,146 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	fileA := filepath.Join(dir, "a.json")
+	fileB := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(fileA, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(fileB, []byte("hello world"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	encA256 := "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+	encB256 := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	encA512 := "27c74670adb75075fad058d5ceaf7b20c4e7786c83bae8a32f626f9782af34c9a33c2046ef60fd2a7878d378e29fec851806bbd9a67878f3a9f1cda4830763fd"
+	manifest := filepath.Join(dir, "manifest")
+	if err := os.WriteFile(manifest, []byte(
+		"sha256:"+encA256+"  "+fileA+"\n"+
+			encB256+" *"+fileB+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	manifestBad := filepath.Join(dir, "manifest-bad")
+	if err := os.WriteFile(manifestBad, []byte(
+		"sha256:"+encB256+"  "+fileA+"\n"+
+			"sha512:"+encA512+"  "+fileA+"\n"+
+			encB256+"  "+filepath.Join(dir, "missing")+"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	manifestCRLF := filepath.Join(dir, "manifest-crlf")
+	if err := os.WriteFile(manifestCRLF, []byte(
+		"sha256:"+encA256+"  "+fileA+"\r\n"+
+			encB256+"  "+fileB+"\r\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	tt := []struct {
+		name   string
+		args   []string
+		stdin  string
+		expect string
+		rc     int
+	}{
+		{
+			name:   "stdin",
+			stdin:  "{}",
+			expect: "sha256:" + encA256 + "  -\n",
+		},
+		{
+			name:   "files",
+			args:   []string{fileA, fileB},
+			expect: "sha256:" + encA256 + "  " + fileA + "\nsha256:" + encB256 + "  " + fileB + "\n",
+		},
+		{
+			name:   "sum-format",
+			args:   []string{"-format", "sum", fileB},
+			expect: encB256 + "  " + fileB + "\n",
+		},
+		{
+			name:   "sha512",
+			args:   []string{"-a", "sha512", fileA},
+			expect: "sha512:" + encA512 + "  " + fileA + "\n",
+		},
+		{
+			name: "missing-file",
+			args: []string{filepath.Join(dir, "missing")},
+			rc:   1,
+		},
+		{
+			name: "unknown-algorithm",
+			args: []string{"-algorithm", "unknown", fileA},
+			rc:   2,
+		},
+		{
+			name: "unknown-format",
+			args: []string{"-format", "unknown", fileA},
+			rc:   2,
+		},
+		{
+			name:   "check",
+			args:   []string{"--check", manifest},
+			expect: fileA + ": OK\n" + fileB + ": OK\n",
+		},
+		{
+			name:  "check-stdin",
+			args:  []string{"-c", "-quiet"},
+			stdin: "sha256:" + encA256 + "  " + fileA + "\n",
+		},
+		{
+			name:   "check-failed",
+			args:   []string{"-check", manifestBad},
+			expect: fileA + ": FAILED\n" + fileA + ": OK\n" + filepath.Join(dir, "missing") + ": FAILED open or read\n",
+			rc:     1,
+		},
+		{
+			name:   "check-crlf",
+			args:   []string{"-check", manifestCRLF},
+			expect: fileA + ": OK\n" + fileB + ": OK\n",
+		},
+		{
+			name:   "check-missing-manifest",
+			args:   []string{"-check", filepath.Join(dir, "missing"), manifest},
+			expect: fileA + ": OK\n" + fileB + ": OK\n",
+			rc:     1,
+		},
+		{
+			name:  "check-invalid-line",
+			args:  []string{"-check"},
+			stdin: "invalid\n",
+			rc:    1,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			rc := run(tc.args, strings.NewReader(tc.stdin), stdout, stderr)
+			if rc != tc.rc {
+				t.Errorf("expected rc %d, received %d, stderr: %s", tc.rc, rc, stderr.String())
+			}
+			if stdout.String() != tc.expect {
+				t.Errorf("expected output:\n%s\nreceived:\n%s", tc.expect, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// Descriptor is a minimal OCI content descriptor as defined by the OCI [image-spec descriptor].
+// Only the media type, digest, size, and annotations are included.
+// Other fields are ignored when unmarshaling JSON.
+//
+// [image-spec descriptor]: https://github.com/opencontainers/image-spec/blob/v1.1.1/descriptor.md
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`             // MediaType is the media type of the referenced content.
+	Digest      Digest            `json:"digest"`                // Digest is the digest of the referenced content.
+	Size        int64             `json:"size"`                  // Size is the length of the referenced content in bytes.
+	Annotations map[string]string `json:"annotations,omitempty"` // Annotations contains arbitrary metadata for the descriptor.
+}
+
+// MediaTypeRegexp validates a media type follows the RFC 6838 syntax required by the OCI image-spec.
+var MediaTypeRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]{0,126}/[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]{0,126}$`)
+
+// Validate verifies the descriptor has a valid media type, a non-zero digest, and a non-negative size.
+func (d Descriptor) Validate() error {
+	if !MediaTypeRegexp.MatchString(d.MediaType) {
+		return fmt.Errorf("%w: %s", ErrMediaTypeInvalid, d.MediaType)
+	}
+	if d.Digest.IsZero() {
+		return fmt.Errorf("%w: digest is not set", ErrDigestInvalid)
+	}
+	if d.Size < 0 {
+		return fmt.Errorf("%w: %d", ErrSizeInvalid, d.Size)
+	}
+	return nil
+}
+
+// Verifier returns a [Verifier] for the referenced content that enforces both the size and digest of the descriptor.
+// The descriptor is validated first, so an untrusted descriptor cannot disable the size limit with a negative size.
+func (d Descriptor) Verifier(r io.Reader) (*Verifier, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return NewVerifierSize(r, d.Digest, d.Size), nil
+}
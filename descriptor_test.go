@@ -0,0 +1,224 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestDescriptorJSON(t *testing.T) {
+	tt := []struct {
+		name   string
+		in     string
+		expect Descriptor
+		out    string
+		err    error
+	}{
+		{
+			name: "image-spec-example",
+			in: `{
+  "mediaType": "application/vnd.oci.image.manifest.v1+json",
+  "size": 7682,
+  "digest": "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+  "urls": [
+    "https://example.com/example-manifest"
+  ]
+}`,
+			expect: Descriptor{
+				MediaType: "application/vnd.oci.image.manifest.v1+json",
+				Digest:    Digest{alg: "sha256", enc: "5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"},
+				Size:      7682,
+			},
+			out: `{"mediaType":"application/vnd.oci.image.manifest.v1+json","digest":"sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270","size":7682}`,
+		},
+		{
+			name: "annotations",
+			in:   `{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2,"annotations":{"org.example":"value"}}`,
+			expect: Descriptor{
+				MediaType:   "application/vnd.oci.empty.v1+json",
+				Digest:      Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"},
+				Size:        2,
+				Annotations: map[string]string{"org.example": "value"},
+			},
+			out: `{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2,"annotations":{"org.example":"value"}}`,
+		},
+		{
+			name: "invalid-digest",
+			in:   `{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136f","size":2}`,
+			err:  ErrEncodingInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var d Descriptor
+			err := json.Unmarshal([]byte(tc.in), &d)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if d.MediaType != tc.expect.MediaType || !d.Digest.Equal(tc.expect.Digest) || d.Size != tc.expect.Size || len(d.Annotations) != len(tc.expect.Annotations) {
+				t.Errorf("expected %v, received %v", tc.expect, d)
+			}
+			for k, v := range tc.expect.Annotations {
+				if d.Annotations[k] != v {
+					t.Errorf("annotation %s, expected %s, received %s", k, v, d.Annotations[k])
+				}
+			}
+			out, err := json.Marshal(d)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			if string(out) != tc.out {
+				t.Errorf("expected %s, received %s", tc.out, string(out))
+			}
+		})
+	}
+}
+
+func TestDescriptorValidate(t *testing.T) {
+	dig := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	tt := []struct {
+		name string
+		d    Descriptor
+		err  error
+	}{
+		{
+			name: "valid",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: 2},
+		},
+		{
+			name: "empty-size",
+			d:    Descriptor{MediaType: "application/octet-stream", Digest: dig, Size: 0},
+		},
+		{
+			name: "missing-media-type",
+			d:    Descriptor{Digest: dig, Size: 2},
+			err:  ErrMediaTypeInvalid,
+		},
+		{
+			name: "media-type-no-subtype",
+			d:    Descriptor{MediaType: "application", Digest: dig, Size: 2},
+			err:  ErrMediaTypeInvalid,
+		},
+		{
+			name: "media-type-parameter",
+			d:    Descriptor{MediaType: "application/json; charset=utf-8", Digest: dig, Size: 2},
+			err:  ErrMediaTypeInvalid,
+		},
+		{
+			name: "missing-digest",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Size: 2},
+			err:  ErrDigestInvalid,
+		},
+		{
+			name: "negative-size",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: -1},
+			err:  ErrSizeInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.d.Validate()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+		})
+	}
+}
+
+func TestDescriptorVerifier(t *testing.T) {
+	dig := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	tt := []struct {
+		name string
+		d    Descriptor
+		in   []byte
+		err  error
+	}{
+		{
+			name: "valid",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: 2},
+			in:   []byte("{}"),
+		},
+		{
+			name: "too-long",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: 1},
+			in:   []byte("{}"),
+			err:  ErrSizeExceeded,
+		},
+		{
+			name: "too-short",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: 3},
+			in:   []byte("{}"),
+			err:  ErrSizeShort,
+		},
+		{
+			name: "mismatch",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: 2},
+			in:   []byte("[]"),
+			err:  ErrDigestMismatch,
+		},
+		{
+			name: "negative-size",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: -1},
+			in:   []byte("{}"),
+			err:  ErrSizeInvalid,
+		},
+		{
+			name: "max-size",
+			d:    Descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: dig, Size: math.MaxInt64},
+			in:   []byte("{}"),
+			err:  ErrSizeShort,
+		},
+		{
+			name: "invalid-media-type",
+			d:    Descriptor{MediaType: "invalid", Digest: dig, Size: 2},
+			in:   []byte("{}"),
+			err:  ErrMediaTypeInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := tc.d.Verifier(bytes.NewReader(tc.in))
+			if err == nil {
+				_, err = io.ReadAll(v)
+			}
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected err: %v", err)
+			}
+		})
+	}
+}
@@ -15,19 +15,34 @@
 package digest
 
 import (
+	"context"
+	"crypto/subtle"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
+	"log/slog"
 	"regexp"
+	"slices"
 	"strings"
 )
 
 // Digest is the combination of an algorithm and the encoded hash value.
+// A digest using a custom algorithm from a [Registry] other than the default registry references that registry.
+// Equal digests from different registries are therefore not equal with == and are different map keys,
+// use [Digest.Equal] to compare by value.
 type Digest struct {
 	alg string
 	enc string
+	reg *Registry // reg is nil for the default registry
 }
 
+// binaryTextTag is the first byte of a binary encoded digest containing the text form.
+// The uvarint length of an algorithm name is never zero.
+const binaryTextTag = 0x00
+
 var (
 	DigestRegexp         = regexp.MustCompile(`[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]+`)       // DigestRegexp validates a digest string follows the OCI character set.
 	DigestRegexpAnchored = regexp.MustCompile(`^` + DigestRegexp.String() + `$`)                     // DigestRegexpAnchored is [DigestRegexp] with the beginning and end of the string anchored.
@@ -37,7 +52,7 @@ var (
 // NewDigest creates a [Digest] from an algorithm and the associated [hash.Hash].
 // This will fail if the algorithm is not valid or the hash does not match.
 func NewDigest(alg Algorithm, h hash.Hash) (Digest, error) {
-	ai, _, err := algorithmInfoLookup(alg.name)
+	ai, err := alg.info()
 	if err != nil {
 		return Digest{}, err
 	}
@@ -51,13 +66,14 @@ func NewDigest(alg Algorithm, h hash.Hash) (Digest, error) {
 	return Digest{
 		alg: alg.name,
 		enc: enc,
+		reg: alg.reg,
 	}, nil
 }
 
 // NewDigestFromEncoded creates a [Digest] from an algorithm and the already encoded string.
 // This will fail if the algorithm is not valid or the encoding does not match the algorithm requirements.
 func NewDigestFromEncoded(alg Algorithm, encoded string) (Digest, error) {
-	ai, _, err := algorithmInfoLookup(alg.name)
+	ai, err := alg.info()
 	if err != nil {
 		return Digest{}, err
 	}
@@ -67,6 +83,7 @@ func NewDigestFromEncoded(alg Algorithm, encoded string) (Digest, error) {
 	return Digest{
 		alg: alg.name,
 		enc: encoded,
+		reg: alg.reg,
 	}, nil
 }
 
@@ -80,6 +97,12 @@ func FromReader(rd io.Reader) (Digest, error) {
 	return Canonical.FromReader(rd)
 }
 
+// FromReaderContext generates a [Digest] from the canonical algorithm using the provided reader.
+// See [Algorithm.FromReaderContext] for details on the context handling.
+func FromReaderContext(ctx context.Context, rd io.Reader) (Digest, error) {
+	return Canonical.FromReaderContext(ctx, rd)
+}
+
 // FromString generates a [Digest] from the canonical algorithm using the provided string.
 func FromString(s string) (Digest, error) {
 	return Canonical.FromString(s)
@@ -88,31 +111,52 @@ func FromString(s string) (Digest, error) {
 // Parse validates the string representation of a [Digest] and returns the parsed value.
 // An empty string will not fail but will return an empty [Digest].
 // This will fail if the string does not match the [DigestRegexp] requirements,
-// the algorithm was not already registered, or the encoding does not match the algorithm requirements.
+// the algorithm was not already registered in the default [Registry], or the encoding does not match the algorithm requirements.
 func Parse(s string) (Digest, error) {
-	if s == "" {
-		return Digest{}, nil
-	}
-	algPart, encPart, ok := strings.Cut(s, ":")
-	if !ok {
-		return Digest{}, fmt.Errorf("%w: %s", ErrDigestInvalid, s)
-	}
-	ai, _, err := algorithmInfoLookup(algPart)
-	if err != nil {
-		return Digest{}, err
-	}
-	if ai.enc == nil || !ai.enc.Validate(encPart) {
-		return Digest{}, fmt.Errorf("%w: %s", ErrEncodingInvalid, encPart)
-	}
-	return Digest{
-		alg: algPart,
-		enc: encPart,
-	}, nil
+	return defaultRegistry.Parse(s)
+}
+
+// Sort sorts a slice of digests in place using [Digest.Compare].
+func Sort(digests []Digest) {
+	slices.SortFunc(digests, Digest.Compare)
+}
+
+// Unique sorts a slice of digests and removes duplicates, returning the modified slice.
+// Like [slices.Compact], the input slice is modified and the removed elements are zeroed.
+func Unique(digests []Digest) []Digest {
+	Sort(digests)
+	return slices.CompactFunc(digests, Digest.Equal)
 }
 
 // Algorithm returns the [Algorithm] portion of the digest.
 func (d Digest) Algorithm() Algorithm {
-	return Algorithm{name: d.alg}
+	return Algorithm{name: d.alg, reg: d.reg}
+}
+
+// AppendBinary is used to output the binary encoding of the digest to the byte slice.
+// The encoding is the length of the algorithm name as a uvarint, the algorithm name, and the raw hash sum.
+// When the encoder for the algorithm does not implement [Decoder], the encoding is a zero byte followed by the text form of the digest.
+// Since [encoding/gob] prefers the binary encoding, this allows any valid digest to be gob encoded.
+// If the input byte slice is nil, a new slice may be allocated.
+// This will return an unmodified byte slice with the digest is the zero value.
+func (d Digest) AppendBinary(b []byte) ([]byte, error) {
+	if d.IsZero() {
+		if b == nil {
+			b = []byte{}
+		}
+		return b, nil
+	}
+	sum, err := d.Bytes()
+	if errors.Is(err, ErrDecodeUnsupported) {
+		b = append(b, binaryTextTag)
+		return d.AppendText(b)
+	}
+	if err != nil {
+		return b, err
+	}
+	b = binary.AppendUvarint(b, uint64(len(d.alg)))
+	b = append(b, d.alg...)
+	return append(b, sum...), nil
 }
 
 // AppendText is used to output the current value of the digest to the byte slice.
@@ -132,6 +176,26 @@ func (d Digest) AppendText(b []byte) ([]byte, error) {
 	return fmt.Appendf(b, "%s:%s", d.alg, d.enc), nil
 }
 
+// Bytes returns the hash sum decoded from the encoded portion of the digest.
+// This will fail if the digest is invalid or the encoder for the algorithm does not implement [Decoder].
+func (d Digest) Bytes() ([]byte, error) {
+	if d.alg == "" || d.enc == "" {
+		return nil, ErrDigestInvalid
+	}
+	return d.Algorithm().Decode(d.enc)
+}
+
+// Compare returns an integer comparing two digests, compatible with [slices.SortFunc].
+// Digests are ordered by the algorithm name, and then by the encoded value.
+// The result is 0 if d == cmp, -1 if d < cmp, and +1 if d > cmp.
+// The zero value sorts before all other digests.
+func (d Digest) Compare(cmp Digest) int {
+	if c := strings.Compare(d.alg, cmp.alg); c != 0 {
+		return c
+	}
+	return strings.Compare(d.enc, cmp.enc)
+}
+
 // Encoded returns the encoded portion of the digest.
 func (d Digest) Encoded() string {
 	return d.enc
@@ -142,17 +206,114 @@ func (d Digest) Equal(cmp Digest) bool {
 	return d.alg == cmp.alg && d.enc == cmp.enc
 }
 
+// EqualConstantTime returns true if two digests have the same algorithm name and hash sum.
+// The hash sums are compared in constant time with [subtle.ConstantTimeCompare].
+// If the encoding cannot be decoded, the encoded values are compared in constant time instead.
+// The algorithm name and the length of the values are not considered secret.
+func (d Digest) EqualConstantTime(cmp Digest) bool {
+	if d.alg != cmp.alg {
+		return false
+	}
+	a, errA := d.Bytes()
+	b, errB := cmp.Bytes()
+	if errA != nil || errB != nil {
+		return subtle.ConstantTimeCompare([]byte(d.enc), []byte(cmp.enc)) == 1
+	}
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Format implements [fmt.Formatter].
+// The %s and %v verbs output the full digest.
+// A precision, e.g. %.12s, outputs the encoded value truncated to that length without the algorithm.
+// The %+v verb includes the size of the algorithm in bytes.
+// The %q, %x, and %X verbs format the full digest string the same as a string value.
+// The %#v verb outputs the Go syntax representation of the struct.
+func (d Digest) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		reg := "(*digest.Registry)(nil)"
+		if d.reg != nil {
+			reg = fmt.Sprintf("(*digest.Registry)(%p)", d.reg)
+		}
+		fmt.Fprintf(f, "digest.Digest{alg:%q, enc:%q, reg:%s}", d.alg, d.enc, reg)
+		return
+	}
+	switch verb {
+	case 's', 'v':
+		out := d.String()
+		if p, ok := f.Precision(); ok {
+			out = d.enc
+			if p < len(out) {
+				out = out[:p]
+			}
+		} else if verb == 'v' && f.Flag('+') && out != "" {
+			out = fmt.Sprintf("%s (%d bytes)", out, d.Algorithm().Size())
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), out)
+	case 'q', 'x', 'X':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), d.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(digest.Digest=%s)", verb, d.String())
+	}
+}
+
 // IsZero returns true if the digest has a zero value for the algorithm name and encoded value.
 func (d Digest) IsZero() bool {
 	return d.alg == "" && d.enc == ""
 }
 
+// LogShort returns a [slog.LogValuer] for the digest with the encoded value truncated to at most n characters.
+// This is useful for logging short identifiers.
+func (d Digest) LogShort(n int) slog.LogValuer {
+	return digestLogShort{d: d, n: n}
+}
+
+// LogValue implements [slog.LogValuer], returning a group with the algorithm and encoded attributes.
+// The zero value returns an empty group.
+func (d Digest) LogValue() slog.Value {
+	if d.IsZero() {
+		return slog.GroupValue()
+	}
+	return slog.GroupValue(
+		slog.String("algorithm", d.alg),
+		slog.String("encoded", d.enc),
+	)
+}
+
+// MarshalBinary returns the binary encoding of the digest as a byte slice.
+// This is equivalent to d.AppendBinary(nil).
+func (d Digest) MarshalBinary() (data []byte, err error) {
+	return d.AppendBinary(nil)
+}
+
 // MarshalText returns the text encoding of the digest as a byte slice.
 // This is equivalent to d.AppendText(nil).
 func (d Digest) MarshalText() (text []byte, err error) {
 	return d.AppendText(nil)
 }
 
+// Scan implements [database/sql.Scanner], parsing a string or byte slice with [Parse].
+// A NULL value is scanned as the zero value.
+func (d *Digest) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*d = Digest{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrDigestInvalid, src)
+	}
+	newD, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = newD
+	return nil
+}
+
 // String returns the string encoding of the digest.
 // If the algorithm name or encoding value is the zero value, an empty string is returned.
 func (d Digest) String() string {
@@ -162,6 +323,47 @@ func (d Digest) String() string {
 	return fmt.Sprintf("%s:%s", d.alg, d.enc)
 }
 
+// UnmarshalBinary parses the binary encoding of a digest and replaces the digest.
+// The text form from [Digest.AppendBinary] is also accepted, along with the plain text from [Digest.MarshalText],
+// which allows gob data encoded before the binary encoding was added to be decoded.
+// The algorithm must be registered in the default [Registry] and the hash sum must match the algorithm size.
+// An empty byte slice is decoded as the zero value.
+func (d *Digest) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*d = Digest{}
+		return nil
+	}
+	if data[0] == binaryTextTag {
+		return d.UnmarshalText(data[1:])
+	}
+	if DigestRegexpAnchored.Match(data) {
+		return d.UnmarshalText(data)
+	}
+	l, n := binary.Uvarint(data)
+	if n <= 0 || l > uint64(len(data)-n) {
+		return fmt.Errorf("%w: invalid binary encoding", ErrDigestInvalid)
+	}
+	name := string(data[n : n+int(l)])
+	sum := data[n+int(l):]
+	alg, err := AlgorithmLookup(name)
+	if err != nil {
+		return err
+	}
+	if len(sum) != alg.Size() {
+		return fmt.Errorf("%w: expected %d bytes for %s, received %d", ErrEncodingInvalid, alg.Size(), name, len(sum))
+	}
+	enc, err := alg.Encode(sum)
+	if err != nil {
+		return err
+	}
+	*d = Digest{
+		alg: alg.name,
+		enc: enc,
+		reg: alg.reg,
+	}
+	return nil
+}
+
 // UnmarshalText parses a given digest text with [Parse] and replaces the digest.
 // This is used by marshalers.
 // An invalid digest string will case the marshaler to fail.
@@ -174,6 +376,32 @@ func (d *Digest) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// Value implements [database/sql/driver.Valuer], returning the string encoding of the digest.
+// The zero value returns NULL.
+func (d Digest) Value() (driver.Value, error) {
+	if d.IsZero() {
+		return nil, nil
+	}
+	if d.alg == "" || d.enc == "" {
+		return nil, ErrDigestInvalid
+	}
+	return d.String(), nil
+}
+
+type digestLogShort struct {
+	d Digest
+	n int
+}
+
+// LogValue implements [slog.LogValuer] with a truncated encoded value.
+func (s digestLogShort) LogValue() slog.Value {
+	d := s.d
+	if s.n >= 0 && len(d.enc) > s.n {
+		d.enc = d.enc[:s.n]
+	}
+	return d.LogValue()
+}
+
 // Digester is used to calculate a digest for an algorithm.
 type Digester interface {
 	io.Writer
@@ -15,16 +15,26 @@
 package digest
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"hash"
+	"log/slog"
 	"strings"
 	"testing"
 )
 
 // Verify interface implementation
-var _ Digester = Writer{}
+var (
+	_ Digester      = Writer{}
+	_ sql.Scanner   = (*Digest)(nil)
+	_ driver.Valuer = Digest{}
+)
 
 func TestNewDigest(t *testing.T) {
 	emptyJSON := sha256.New()
@@ -308,6 +318,29 @@ func TestEqual(t *testing.T) {
 				enc: "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
 			},
 		},
+		{
+			name: "unknown-same",
+			a: Digest{
+				alg: "unknown",
+				enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			},
+			b: Digest{
+				alg: "unknown",
+				enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			},
+			eq: true,
+		},
+		{
+			name: "unknown-different",
+			a: Digest{
+				alg: "unknown",
+				enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			},
+			b: Digest{
+				alg: "unknown",
+				enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			},
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -315,6 +348,10 @@ func TestEqual(t *testing.T) {
 			if eq != tc.eq {
 				t.Errorf("expected Equal %t, received %t", tc.eq, eq)
 			}
+			eq = tc.a.EqualConstantTime(tc.b)
+			if eq != tc.eq {
+				t.Errorf("expected EqualConstantTime %t, received %t", tc.eq, eq)
+			}
 		})
 	}
 }
@@ -561,3 +598,582 @@ func TestUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+// encodeNoDecode is an [Encoder] that does not implement [Decoder].
+type encodeNoDecode struct {
+	hex EncodeHex
+}
+
+func (e encodeNoDecode) Encode(p []byte) (string, error) { return e.hex.Encode(p) }
+func (e encodeNoDecode) Validate(s string) bool          { return e.hex.Validate(s) }
+
+func TestBytes(t *testing.T) {
+	r := NewRegistry()
+	noDecode, err := r.Register("no-decode", encodeNoDecode{hex: EncodeHex{Len: 64}}, sha256.New)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	noDecodeDig, err := noDecode.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	sum := sha256.Sum256([]byte("{}"))
+	tt := []struct {
+		name   string
+		d      Digest
+		expect []byte
+		err    error
+	}{
+		{
+			name: "empty",
+			err:  ErrDigestInvalid,
+		},
+		{
+			name: "sha256",
+			d: Digest{
+				alg: "sha256",
+				enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			},
+			expect: sum[:],
+		},
+		{
+			name: "unknown",
+			d: Digest{
+				alg: "unknown",
+				enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			},
+			err: ErrAlgorithmUnknown,
+		},
+		{
+			name: "no-decode",
+			d:    noDecodeDig,
+			err:  ErrDecodeUnsupported,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.d.Bytes()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !bytes.Equal(out, tc.expect) {
+				t.Errorf("expected %x, received %x", tc.expect, out)
+			}
+		})
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	tt := []struct {
+		name   string
+		d      Digest
+		expect []byte
+		err    error
+	}{
+		{
+			name:   "empty",
+			expect: []byte{},
+		},
+		{
+			name: "invalid",
+			d: Digest{
+				alg: "sha256",
+				enc: "",
+			},
+			err: ErrDigestInvalid,
+		},
+		{
+			name: "sha256-empty",
+			d: Digest{
+				alg: "sha256",
+				enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			},
+			expect: append([]byte("\x06sha256"), []byte{
+				0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24,
+				0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55,
+			}...),
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.d.MarshalBinary()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !bytes.Equal(out, tc.expect) {
+				t.Errorf("expected %x, received %x", tc.expect, out)
+			}
+			prefix := []byte("prefix")
+			out, err = tc.d.AppendBinary(prefix)
+			if err != nil {
+				t.Fatalf("unexpected append err: %v", err)
+			}
+			if !bytes.Equal(out, append(prefix, tc.expect...)) {
+				t.Errorf("expected %x, received %x", append(prefix, tc.expect...), out)
+			}
+			var d Digest
+			if err := d.UnmarshalBinary(tc.expect); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			if !d.Equal(tc.d) {
+				t.Errorf("expected %s, received %s", tc.d.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestUnmarshalBinary(t *testing.T) {
+	sum := sha256.Sum256([]byte("{}"))
+	tt := []struct {
+		name   string
+		in     []byte
+		expect Digest
+		err    error
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "sha256",
+			in:   append([]byte("\x06sha256"), sum[:]...),
+			expect: Digest{
+				alg: "sha256",
+				enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			},
+		},
+		{
+			name: "short-name",
+			in:   []byte("\x10sha256"),
+			err:  ErrDigestInvalid,
+		},
+		{
+			name: "unknown",
+			in:   append([]byte("\x07unknown"), sum[:]...),
+			err:  ErrAlgorithmUnknown,
+		},
+		{
+			name: "short-sum",
+			in:   append([]byte("\x06sha256"), sum[:31]...),
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "long-sum",
+			in:   append(append([]byte("\x06sha256"), sum[:]...), 0),
+			err:  ErrEncodingInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var d Digest
+			err := d.UnmarshalBinary(tc.in)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !d.Equal(tc.expect) {
+				t.Errorf("expected %s, received %s", tc.expect.String(), d.String())
+			}
+		})
+	}
+}
+
+func TestMarshalGob(t *testing.T) {
+	type testData struct {
+		Name   string
+		Digest Digest
+	}
+	in := testData{
+		Name: "gob",
+		Digest: Digest{
+			alg: "sha512",
+			enc: "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+		},
+	}
+	buf := bytes.Buffer{}
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	out := testData{}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if out.Name != in.Name || !out.Digest.Equal(in.Digest) {
+		t.Errorf("expected %v, received %v", in, out)
+	}
+}
+
+func TestMarshalBinaryText(t *testing.T) {
+	name := "sha256-no-decode"
+	alg, err := AlgorithmRegister(name, encodeNoDecode{hex: EncodeHex{Len: 64}}, sha256.New)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = DefaultRegistry().Unregister(name)
+	})
+	d, err := alg.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	// encoders without a decoder use the tagged text form
+	out, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	expect := append([]byte{binaryTextTag}, d.String()...)
+	if !bytes.Equal(out, expect) {
+		t.Errorf("expected %x, received %x", expect, out)
+	}
+	var d2 Digest
+	if err := d2.UnmarshalBinary(out); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if !d2.Equal(d) {
+		t.Errorf("expected %s, received %s", d.String(), d2.String())
+	}
+	// gob encoding succeeds
+	type testData struct {
+		Digest Digest
+	}
+	buf := bytes.Buffer{}
+	if err := gob.NewEncoder(&buf).Encode(testData{Digest: d}); err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	gobOut := testData{}
+	if err := gob.NewDecoder(&buf).Decode(&gobOut); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if !gobOut.Digest.Equal(d) {
+		t.Errorf("expected %s, received %s", d.String(), gobOut.Digest.String())
+	}
+	// plain text from MarshalText, e.g. older gob data, is accepted
+	for _, in := range []Digest{d, {alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}} {
+		text, err := in.MarshalText()
+		if err != nil {
+			t.Fatalf("failed to marshal text: %v", err)
+		}
+		var d3 Digest
+		if err := d3.UnmarshalBinary(text); err != nil {
+			t.Fatalf("failed to unmarshal text: %v", err)
+		}
+		if !d3.Equal(in) {
+			t.Errorf("expected %s, received %s", in.String(), d3.String())
+		}
+	}
+	// invalid text is rejected
+	var d4 Digest
+	if err := d4.UnmarshalBinary([]byte("\x00sha256:invalid")); err == nil {
+		t.Errorf("unmarshal of invalid text did not fail")
+	}
+}
+
+func TestSQL(t *testing.T) {
+	d256 := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	tt := []struct {
+		name   string
+		src    any
+		expect Digest
+		err    error
+	}{
+		{
+			name: "null",
+		},
+		{
+			name:   "string",
+			src:    d256.String(),
+			expect: d256,
+		},
+		{
+			name:   "bytes",
+			src:    []byte(d256.String()),
+			expect: d256,
+		},
+		{
+			name: "empty-string",
+			src:  "",
+		},
+		{
+			name: "unknown-alg",
+			src:  "unknown:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+			err:  ErrAlgorithmUnknown,
+		},
+		{
+			name: "invalid-encoding",
+			src:  "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8",
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "unsupported-type",
+			src:  42,
+			err:  ErrDigestInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d := Digest{alg: "sha512", enc: "previous"}
+			err := d.Scan(tc.src)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !d.Equal(tc.expect) {
+				t.Errorf("expected %s, received %s", tc.expect.String(), d.String())
+			}
+			v, err := d.Value()
+			if err != nil {
+				t.Fatalf("unexpected value err: %v", err)
+			}
+			if tc.expect.IsZero() {
+				if v != nil {
+					t.Errorf("expected nil value, received %v", v)
+				}
+			} else if v != tc.expect.String() {
+				t.Errorf("expected value %s, received %v", tc.expect.String(), v)
+			}
+		})
+	}
+	t.Run("invalid-value", func(t *testing.T) {
+		_, err := Digest{alg: "sha256"}.Value()
+		if !errors.Is(err, ErrDigestInvalid) {
+			t.Errorf("expected err %v, received %v", ErrDigestInvalid, err)
+		}
+	})
+}
+
+func TestLogValue(t *testing.T) {
+	d := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	tt := []struct {
+		name   string
+		v      any
+		expect string
+	}{
+		{
+			name:   "digest",
+			v:      d,
+			expect: "level=INFO msg=test d.algorithm=sha256 d.encoded=44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a\n",
+		},
+		{
+			name:   "zero",
+			v:      Digest{},
+			expect: "level=INFO msg=test\n",
+		},
+		{
+			name:   "short",
+			v:      d.LogShort(12),
+			expect: "level=INFO msg=test d.algorithm=sha256 d.encoded=44136fa355b3\n",
+		},
+		{
+			name:   "short-long",
+			v:      d.LogShort(100),
+			expect: "level=INFO msg=test d.algorithm=sha256 d.encoded=44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a\n",
+		},
+		{
+			name:   "algorithm",
+			v:      SHA512,
+			expect: "level=INFO msg=test d=sha512\n",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+			log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			log.Info("test", "d", tc.v)
+			if buf.String() != tc.expect {
+				t.Errorf("expected %q, received %q", tc.expect, buf.String())
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	d := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	tt := []struct {
+		name   string
+		format string
+		d      Digest
+		expect string
+	}{
+		{
+			name:   "string",
+			format: "%s",
+			d:      d,
+			expect: "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		},
+		{
+			name:   "value",
+			format: "%v",
+			d:      d,
+			expect: "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		},
+		{
+			name:   "value-plus",
+			format: "%+v",
+			d:      d,
+			expect: "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a (32 bytes)",
+		},
+		{
+			name:   "short",
+			format: "%.12s",
+			d:      d,
+			expect: "44136fa355b3",
+		},
+		{
+			name:   "short-longer",
+			format: "%.100s",
+			d:      d,
+			expect: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		},
+		{
+			name:   "short-padded",
+			format: "[%-14.12s]",
+			d:      d,
+			expect: "[44136fa355b3  ]",
+		},
+		{
+			name:   "quoted",
+			format: "%q",
+			d:      d,
+			expect: `"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"`,
+		},
+		{
+			name:   "hex",
+			format: "%x",
+			d:      Digest{alg: "sha256", enc: "ab"},
+			expect: "7368613235363a6162",
+		},
+		{
+			name:   "hex-upper-space",
+			format: "% X",
+			d:      Digest{alg: "sha256", enc: "ab"},
+			expect: "73 68 61 32 35 36 3A 61 62",
+		},
+		{
+			name:   "go-syntax",
+			format: "%#v",
+			d:      d,
+			expect: `digest.Digest{alg:"sha256", enc:"44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", reg:(*digest.Registry)(nil)}`,
+		},
+		{
+			name:   "zero",
+			format: "[%+v]",
+			expect: "[]",
+		},
+		{
+			name:   "unsupported",
+			format: "%d",
+			d:      d,
+			expect: "%!d(digest.Digest=sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a)",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out := fmt.Sprintf(tc.format, tc.d)
+			if out != tc.expect {
+				t.Errorf("expected %s, received %s", tc.expect, out)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	d1 := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	d2 := Digest{alg: "sha256", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	d3 := Digest{alg: "sha512", enc: "27c74670adb75075fad058d5ceaf7b20c4e7786c83bae8a32f626f9782af34c9a33c2046ef60fd2a7878d378e29fec851806bbd9a67878f3a9f1cda4830763fd"}
+	tt := []struct {
+		name   string
+		a, b   Digest
+		expect int
+	}{
+		{
+			name: "zero",
+		},
+		{
+			name:   "zero-first",
+			a:      Digest{},
+			b:      d1,
+			expect: -1,
+		},
+		{
+			name: "same",
+			a:    d1,
+			b:    d1,
+		},
+		{
+			name:   "encoded-less",
+			a:      d1,
+			b:      d2,
+			expect: -1,
+		},
+		{
+			name:   "encoded-greater",
+			a:      d2,
+			b:      d1,
+			expect: 1,
+		},
+		{
+			name:   "algorithm-first",
+			a:      d2,
+			b:      d3,
+			expect: -1,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.a.Compare(tc.b)
+			if c != tc.expect {
+				t.Errorf("expected %d, received %d", tc.expect, c)
+			}
+		})
+	}
+	t.Run("sort", func(t *testing.T) {
+		list := []Digest{d3, d2, d1, d2}
+		Sort(list)
+		expect := []Digest{d1, d2, d2, d3}
+		for i := range expect {
+			if !list[i].Equal(expect[i]) {
+				t.Errorf("entry %d, expected %s, received %s", i, expect[i].String(), list[i].String())
+			}
+		}
+	})
+	t.Run("unique", func(t *testing.T) {
+		list := Unique([]Digest{d3, d2, d1, d2, d3})
+		expect := []Digest{d1, d2, d3}
+		if len(list) != len(expect) {
+			t.Fatalf("expected length %d, received %d", len(expect), len(list))
+		}
+		for i := range expect {
+			if !list[i].Equal(expect[i]) {
+				t.Errorf("entry %d, expected %s, received %s", i, expect[i].String(), list[i].String())
+			}
+		}
+	})
+}
@@ -15,7 +15,10 @@
 package digest
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"strings"
 )
 
 // Encoder is used to generate or verify the encoded portion of a digest for a given algorithm.
@@ -24,11 +27,88 @@ type Encoder interface {
 	Validate(string) bool            // Validate verifies a string matches the encoder requirements.
 }
 
+// Decoder is an optional interface implemented by an [Encoder] to recover the hash sum from the encoded string.
+type Decoder interface {
+	Decode(s string) ([]byte, error) // Decode outputs the hash sum for an encoded string.
+}
+
+// EncodeBase64URL is a base64url encoder using the URL and filename safe alphabet from RFC 4648.
+// Without padding, the hash sum size is derived from Len.
+// With padding, several sum sizes share the same Len, so Size must be set.
+type EncodeBase64URL struct {
+	Len  int  // Len is the length of the encoded text, including any padding.
+	Pad  bool // Pad enables the "=" padding characters.
+	Size int  // Size is the length of the hash sum in bytes, required when Pad is set.
+}
+
+// Decode returns the hash sum for the encoded string.
+// This will fail if the string does not pass Validate.
+func (e EncodeBase64URL) Decode(s string) ([]byte, error) {
+	size, ok := e.size()
+	if !ok || len(s) != e.Len || !isBase64URL(s, e.Pad) {
+		return nil, ErrEncodingInvalid
+	}
+	out, err := e.encoding().Strict().DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrEncodingInvalid, err)
+	}
+	if len(out) != size {
+		return nil, fmt.Errorf("%w: expected %d bytes, received %d", ErrEncodingInvalid, size, len(out))
+	}
+	return out, nil
+}
+
+// Encode outputs the encoded string for the hash sum.
+func (e EncodeBase64URL) Encode(p []byte) (string, error) {
+	size, ok := e.size()
+	if !ok || len(p) != size {
+		return "", ErrEncodingInvalid
+	}
+	return e.encoding().EncodeToString(p), nil
+}
+
+// Validate verifies the string matches the encoded requirements.
+// The string must only contain the characters A-Z, a-z, 0-9, "-", and "_", with "=" padding when Pad is set.
+// The encoding must be canonical, with any unused trailing bits set to zero.
+// The length must match the Len value of EncodeBase64URL, and the decoded hash sum must match the size.
+func (e EncodeBase64URL) Validate(s string) bool {
+	_, err := e.Decode(s)
+	return err == nil
+}
+
+// size returns the length of the hash sum, and false if Len, Pad, and Size are inconsistent.
+func (e EncodeBase64URL) size() (int, bool) {
+	size := e.Size
+	if size == 0 && !e.Pad {
+		size = e.Len * 6 / 8
+	}
+	if size <= 0 || e.encoding().EncodedLen(size) != e.Len {
+		return 0, false
+	}
+	return size, true
+}
+
+func (e EncodeBase64URL) encoding() *base64.Encoding {
+	if e.Pad {
+		return base64.URLEncoding
+	}
+	return base64.RawURLEncoding
+}
+
 // EncodeHex is the hex encoder used by the current registered digest algorithms.
 type EncodeHex struct {
 	Len int // Len is the length of the encoded text, which is 2x the hash sum length.
 }
 
+// Decode returns the hash sum for the encoded string.
+// This will fail if the string does not pass Validate.
+func (e EncodeHex) Decode(s string) ([]byte, error) {
+	if !e.Validate(s) {
+		return nil, ErrEncodingInvalid
+	}
+	return hex.DecodeString(s)
+}
+
 // Encode outputs the encoded string for the hash sum.
 func (e EncodeHex) Encode(p []byte) (string, error) {
 	if len(p)*2 != e.Len {
@@ -47,6 +127,20 @@ func (e EncodeHex) Validate(s string) bool {
 	return false
 }
 
+// isBase64URL verifies the characters are in the base64url alphabet.
+// The decoder from [encoding/base64] ignores "\r" and "\n", even in strict mode, so they must be rejected here.
+func isBase64URL(s string, pad bool) bool {
+	if pad {
+		s = strings.TrimRight(s, "=")
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
 func isHex(s string) bool {
 	for _, r := range s {
 		if (r < 'a' || r > 'f') && (r < '0' || r > '9') {
@@ -15,12 +15,20 @@
 package digest
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
+	"strings"
 	"testing"
 )
 
 // Verify interface implementation
-var _ Encoder = EncodeHex{Len: 32}
+var (
+	_ Encoder = EncodeHex{Len: 32}
+	_ Encoder = EncodeBase64URL{Len: 43}
+	_ Decoder = EncodeHex{Len: 32}
+	_ Decoder = EncodeBase64URL{Len: 43}
+)
 
 func TestEncoderEncode(t *testing.T) {
 	tt := []struct {
@@ -47,6 +55,35 @@ func TestEncoderEncode(t *testing.T) {
 			in:   []byte("hello world"),
 			err:  ErrEncodingInvalid,
 		},
+		{
+			name:   "base64url-valid",
+			enc:    EncodeBase64URL{Len: 6},
+			in:     []byte{0xfb, 0xff, 0xbf, 0x01},
+			expect: "-_-_AQ",
+		},
+		{
+			name:   "base64url-padded",
+			enc:    EncodeBase64URL{Len: 8, Pad: true, Size: 4},
+			in:     []byte{0xfb, 0xff, 0xbf, 0x01},
+			expect: "-_-_AQ==",
+		},
+		{
+			name: "base64url-empty",
+			enc:  EncodeBase64URL{Len: 6},
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "base64url-too-long",
+			enc:  EncodeBase64URL{Len: 6},
+			in:   []byte("hello world"),
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "base64url-missing-pad",
+			enc:  EncodeBase64URL{Len: 6, Pad: true},
+			in:   []byte{0xfb, 0xff, 0xbf, 0x01},
+			err:  ErrEncodingInvalid,
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -94,6 +131,67 @@ func TestEncoderValidate(t *testing.T) {
 			name: "hex-too-short",
 			enc:  EncodeHex{Len: 10},
 		},
+		{
+			name:  "base64url-valid",
+			enc:   EncodeBase64URL{Len: 6},
+			check: "-_-_AQ",
+			valid: true,
+		},
+		{
+			name:  "base64url-padded",
+			enc:   EncodeBase64URL{Len: 8, Pad: true, Size: 4},
+			check: "-_-_AQ==",
+			valid: true,
+		},
+		{
+			name:  "base64url-unexpected-pad",
+			enc:   EncodeBase64URL{Len: 8},
+			check: "-_-_AQ==",
+		},
+		{
+			name:  "base64url-missing-pad",
+			enc:   EncodeBase64URL{Len: 6, Pad: true},
+			check: "-_-_AQ",
+		},
+		{
+			name:  "base64url-std-alphabet",
+			enc:   EncodeBase64URL{Len: 6},
+			check: "+/+/AQ",
+		},
+		{
+			name:  "base64url-non-canonical",
+			enc:   EncodeBase64URL{Len: 6},
+			check: "-_-_AR",
+		},
+		{
+			name:  "base64url-too-long",
+			enc:   EncodeBase64URL{Len: 6},
+			check: "-_-_AQAA",
+		},
+		{
+			name: "base64url-empty",
+			enc:  EncodeBase64URL{},
+		},
+		{
+			name:  "base64url-newline",
+			enc:   EncodeBase64URL{Len: 7},
+			check: "-_-_\nAQ",
+		},
+		{
+			name:  "base64url-carriage-return",
+			enc:   EncodeBase64URL{Len: 7},
+			check: "-_-_AQ\r",
+		},
+		{
+			name:  "base64url-padded-newline",
+			enc:   EncodeBase64URL{Len: 8, Pad: true, Size: 4},
+			check: "-_-_A\nQ=",
+		},
+		{
+			name:  "base64url-pad-in-middle",
+			enc:   EncodeBase64URL{Len: 8, Pad: true, Size: 4},
+			check: "-_=_AQ==",
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -104,3 +202,151 @@ func TestEncoderValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeBase64URLAlgorithm(t *testing.T) {
+	r := NewRegistry()
+	a, err := r.Register("sha256+b64u", EncodeBase64URL{Len: 43}, sha256.New)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	d, err := a.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	expect := "sha256+b64u:RBNvo1WzZ4oRRq0W9-hknpT7T8If536DEMBg9hyq_4o"
+	if d.String() != expect {
+		t.Errorf("expected %s, received %s", expect, d.String())
+	}
+	if !DigestRegexpAnchored.MatchString(d.String()) {
+		t.Errorf("digest does not match the regexp: %s", d.String())
+	}
+	// newlines are ignored by the base64 decoder and must be rejected
+	for _, s := range []string{"sha256+b64u:\n" + strings.Repeat("A", 42), "sha256+b64u:\r" + strings.Repeat("A", 42)} {
+		if _, err := r.Parse(s); err == nil {
+			t.Errorf("parse did not fail on %q", s)
+		}
+	}
+	parsed, err := r.Parse(expect)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if !parsed.Equal(d) {
+		t.Errorf("parse mismatch, expected %s, received %s", d.String(), parsed.String())
+	}
+}
+
+func TestEncodeBase64URLPaddedSize(t *testing.T) {
+	r := NewRegistry()
+	a, err := r.Register("sha256+b64up", EncodeBase64URL{Len: 44, Pad: true, Size: 32}, sha256.New)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	d, err := a.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	expect := "sha256+b64up:RBNvo1WzZ4oRRq0W9-hknpT7T8If536DEMBg9hyq_4o="
+	if d.String() != expect {
+		t.Errorf("expected %s, received %s", expect, d.String())
+	}
+	b, err := d.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if len(b) != 1+len("sha256+b64up")+32 {
+		t.Errorf("unexpected binary length %d", len(b))
+	}
+	// the same encoded length with a 31 or 33 byte sum is rejected
+	for _, s := range []string{
+		"sha256+b64up:" + strings.Repeat("A", 40) + "AQ==",
+		"sha256+b64up:" + strings.Repeat("A", 40) + "AQEA",
+	} {
+		if _, err := r.Parse(s); err == nil {
+			t.Errorf("parse did not fail on %s", s)
+		}
+	}
+	// padding without a size is ambiguous
+	enc := EncodeBase64URL{Len: 44, Pad: true}
+	if enc.Validate(expect[len("sha256+b64up:"):]) {
+		t.Errorf("validate succeeded without a size")
+	}
+	if _, err := enc.Encode(make([]byte, 32)); !errors.Is(err, ErrEncodingInvalid) {
+		t.Errorf("expected err %v, received %v", ErrEncodingInvalid, err)
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	tt := []struct {
+		name   string
+		dec    Decoder
+		in     string
+		expect []byte
+		err    error
+	}{
+		{
+			name:   "hex-valid",
+			dec:    EncodeHex{Len: 10},
+			in:     "68656c6c6f",
+			expect: []byte("hello"),
+		},
+		{
+			name: "hex-invalid-char",
+			dec:  EncodeHex{Len: 10},
+			in:   "68656C6c6f",
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "hex-too-short",
+			dec:  EncodeHex{Len: 10},
+			in:   "68656c",
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name:   "base64url-valid",
+			dec:    EncodeBase64URL{Len: 6},
+			in:     "-_-_AQ",
+			expect: []byte{0xfb, 0xff, 0xbf, 0x01},
+		},
+		{
+			name:   "base64url-padded",
+			dec:    EncodeBase64URL{Len: 8, Pad: true, Size: 4},
+			in:     "-_-_AQ==",
+			expect: []byte{0xfb, 0xff, 0xbf, 0x01},
+		},
+		{
+			name: "base64url-padded-short-sum",
+			dec:  EncodeBase64URL{Len: 8, Pad: true, Size: 5},
+			in:   "-_-_AQ==",
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "base64url-invalid",
+			dec:  EncodeBase64URL{Len: 6},
+			in:   "-_-_A=",
+			err:  ErrEncodingInvalid,
+		},
+		{
+			name: "base64url-newline",
+			dec:  EncodeBase64URL{Len: 7},
+			in:   "-_-_\r\nA",
+			err:  ErrEncodingInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := tc.dec.Decode(tc.in)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !bytes.Equal(out, tc.expect) {
+				t.Errorf("expected %x, received %x", tc.expect, out)
+			}
+		})
+	}
+}
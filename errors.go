@@ -14,17 +14,32 @@
 
 package digest
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
+	// ErrAlgorithmDuplicate is returned when the same algorithm is provided more than once.
+	ErrAlgorithmDuplicate = errors.New("duplicate algorithm")
 	// ErrAlgorithmExists is returned when attempting to register an algorithm twice.
 	ErrAlgorithmExists = errors.New("algorithm is already registered")
 	// ErrAlgorithmInvalidName is returned when attempting to register an algorithm that does not follow the OCI naming requirements.
 	ErrAlgorithmInvalidName = errors.New("invalid algorithm name")
+	// ErrAlgorithmReserved is returned when attempting to remove one of the OCI registered algorithms.
+	ErrAlgorithmReserved = errors.New("algorithm is reserved")
 	// ErrAlgorithmUnknown is returned when trying to use an algorithm name that was not registered.
 	ErrAlgorithmUnknown = errors.New("algorithm is not registered")
+	// ErrDecodeUnsupported is returned when the encoder for an algorithm does not implement [Decoder].
+	ErrDecodeUnsupported = errors.New("encoding does not support decoding")
+	// ErrDigestAmbiguous is returned when a prefix matches more than one digest in a [Set].
+	ErrDigestAmbiguous = errors.New("digest prefix is ambiguous")
 	// ErrDigestInvalid is returned when parsing an invalid digest string or using an undefined digest.
 	ErrDigestInvalid = errors.New("digest is invalid")
+	// ErrDigestMismatch is returned when the content does not match the expected digest.
+	ErrDigestMismatch = errors.New("digest mismatch")
+	// ErrDigestNotFound is returned when a digest or prefix is not found in a [Set].
+	ErrDigestNotFound = errors.New("digest not found")
 	// ErrEncodeInterfaceInvalid is returned when trying to use an invalid encoding interface.
 	ErrEncodeInterfaceInvalid = errors.New("invalid encoding interface")
 	// ErrEncodingInvalid is returned when trying to create a digest with an invalid hex value.
@@ -33,8 +48,59 @@ var (
 	ErrHashFunctionInvalid = errors.New("invalid hash function")
 	// ErrHashInterfaceInvalid is returned when the hash interface is nil or does not return a valid hash.
 	ErrHashInterfaceInvalid = errors.New("invalid hash interface")
+	// ErrJSONInvalid is returned when JSON input is invalid or cannot be canonicalized.
+	ErrJSONInvalid = errors.New("invalid JSON")
+	// ErrKeyUnsupported is returned when a digest cannot be converted to a [Key].
+	ErrKeyUnsupported = errors.New("digest cannot be converted to a key")
+	// ErrMediaTypeInvalid is returned when a media type does not follow the RFC 6838 syntax.
+	ErrMediaTypeInvalid = errors.New("invalid media type")
 	// ErrReaderInvalid is returned when a reader wasn't created with the appropriate function.
 	ErrReaderInvalid = errors.New("invalid reader")
+	// ErrSizeExceeded is returned when the content is larger than the expected size.
+	ErrSizeExceeded = errors.New("content exceeds the expected size")
+	// ErrSizeInvalid is returned when a size is negative.
+	ErrSizeInvalid = errors.New("invalid size")
+	// ErrSizeShort is returned when the content is smaller than the expected size.
+	ErrSizeShort = errors.New("content is shorter than the expected size")
+	// ErrStateInvalid is returned when a serialized digest state cannot be parsed.
+	ErrStateInvalid = errors.New("invalid digest state")
+	// ErrStateUnsupported is returned when the hash for an algorithm cannot be marshaled or unmarshaled.
+	ErrStateUnsupported = errors.New("hash state cannot be marshaled for the algorithm")
 	// ErrWriterInvalid is returned when a writer wasn't created with the appropriate function.
 	ErrWriterInvalid = errors.New("invalid writer")
 )
+
+// MismatchError is returned when the content does not match the expected digest.
+// It matches [ErrDigestMismatch] with [errors.Is].
+type MismatchError struct {
+	Expected Digest // Expected is the digest that was provided by the caller.
+	Actual   Digest // Actual is the digest computed from the content.
+}
+
+// Error returns the error string.
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s: expected %s, received %s", ErrDigestMismatch.Error(), e.Expected.String(), e.Actual.String())
+}
+
+// Is returns true when the target is [ErrDigestMismatch].
+func (e *MismatchError) Is(target error) bool {
+	return target == ErrDigestMismatch
+}
+
+// PartialError is returned when digesting is stopped early by a canceled context.
+// The hash includes exactly the first Size bytes of the content.
+// It wraps the context error, matching [context.Canceled] or [context.DeadlineExceeded] with [errors.Is].
+type PartialError struct {
+	Size int64 // Size is the number of bytes read and hashed before stopping.
+	Err  error // Err is the error from the context.
+}
+
+// Error returns the error string.
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("digest stopped after %d bytes: %v", e.Size, e.Err)
+}
+
+// Unwrap returns the context error.
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
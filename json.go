@@ -0,0 +1,277 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// CanonicalJSON returns the canonical form of the JSON input as defined by the RFC 8785 JSON Canonicalization Scheme (JCS).
+// Object keys are sorted, insignificant whitespace is removed, and strings and numbers are serialized in a consistent format.
+// This will fail if the input is not valid JSON, contains duplicate object keys, or contains numbers that cannot be represented as a float64.
+// Invalid UTF-8 and unpaired surrogate escapes are also rejected, rather than being replaced with U+FFFD.
+func CanonicalJSON(in []byte) ([]byte, error) {
+	if err := jcsCheckUnicode(in); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(in))
+	dec.UseNumber()
+	out, err := jcsValue(dec, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: unexpected data after the JSON value", ErrJSONInvalid)
+	}
+	return out, nil
+}
+
+// FromJSON generates a [Digest] from the canonical algorithm using the exact bytes of the JSON input.
+func FromJSON(raw json.RawMessage) (Digest, error) {
+	return Canonical.FromJSON(raw)
+}
+
+// FromJSONCanonical generates a [Digest] from the canonical algorithm using the [CanonicalJSON] form of the value.
+func FromJSONCanonical(v any) (Digest, error) {
+	return Canonical.FromJSONCanonical(v)
+}
+
+// FromJSON generates a digest on the exact bytes of the JSON input using the algorithm and returns a [Digest].
+// This should be used when the original bytes are available, e.g. a manifest pulled from a registry.
+// This will fail if the algorithm is invalid or the input is not valid JSON.
+func (a Algorithm) FromJSON(raw json.RawMessage) (Digest, error) {
+	if !json.Valid(raw) {
+		return Digest{}, ErrJSONInvalid
+	}
+	return a.FromBytes(raw)
+}
+
+// FromJSONCanonical generates a digest on the [CanonicalJSON] form of the value using the algorithm and returns a [Digest].
+// The value is first marshaled with [json.Marshal], a [json.RawMessage] is canonicalized directly.
+// This will fail if the algorithm is invalid or the value cannot be canonicalized.
+func (a Algorithm) FromJSONCanonical(v any) (Digest, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return Digest{}, fmt.Errorf("%w: %w", ErrJSONInvalid, err)
+	}
+	out, err := CanonicalJSON(raw)
+	if err != nil {
+		return Digest{}, err
+	}
+	return a.FromBytes(out)
+}
+
+// jcsCheckUnicode verifies the input is valid UTF-8 without unpaired surrogates in any \u escape.
+// RFC 8785 requires these to fail, while [encoding/json] silently replaces them with U+FFFD.
+func jcsCheckUnicode(in []byte) error {
+	if !utf8.Valid(in) {
+		return fmt.Errorf("%w: invalid UTF-8", ErrJSONInvalid)
+	}
+	for i := 0; i < len(in); i++ {
+		// a backslash only appears in valid JSON as an escape inside a string
+		if in[i] != '\\' || i+1 >= len(in) {
+			continue
+		}
+		if in[i+1] != 'u' {
+			i++
+			continue
+		}
+		r, ok := jcsEscape(in[i:])
+		if !ok {
+			// invalid escapes are reported by the decoder
+			i++
+			continue
+		}
+		i += 5
+		switch {
+		case utf16.IsSurrogate(r) && r < 0xdc00:
+			low, ok := jcsEscape(in[i+1:])
+			if !ok || low < 0xdc00 || low > 0xdfff {
+				return fmt.Errorf("%w: unpaired surrogate \\u%04x", ErrJSONInvalid, r)
+			}
+			i += 6
+		case utf16.IsSurrogate(r):
+			return fmt.Errorf("%w: unpaired surrogate \\u%04x", ErrJSONInvalid, r)
+		}
+	}
+	return nil
+}
+
+// jcsEscape parses a \uXXXX escape at the start of b.
+func jcsEscape(b []byte) (rune, bool) {
+	if len(b) < 6 || b[0] != '\\' || b[1] != 'u' {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(string(b[2:6]), 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return rune(v), true
+}
+
+// jcsValue reads the next value from the decoder and appends the canonical form to b.
+func jcsValue(dec *json.Decoder, b []byte) ([]byte, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONInvalid, err)
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return jcsObject(dec, b)
+		case '[':
+			return jcsArray(dec, b)
+		default:
+			return nil, fmt.Errorf("%w: unexpected delimiter %s", ErrJSONInvalid, v)
+		}
+	case nil:
+		return append(b, "null"...), nil
+	case bool:
+		return strconv.AppendBool(b, v), nil
+	case json.Number:
+		return jcsNumber(b, v)
+	case string:
+		return jcsString(b, v), nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected token %v", ErrJSONInvalid, tok)
+	}
+}
+
+func jcsArray(dec *json.Decoder, b []byte) ([]byte, error) {
+	var err error
+	b = append(b, '[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b, err = jcsValue(dec, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONInvalid, err)
+	}
+	return append(b, ']'), nil
+}
+
+func jcsObject(dec *json.Decoder, b []byte) ([]byte, error) {
+	type member struct {
+		key   string
+		key16 []uint16
+		value []byte
+	}
+	members := []member{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrJSONInvalid, err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected object key %v", ErrJSONInvalid, tok)
+		}
+		value, err := jcsValue(dec, nil)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member{key: key, key16: utf16.Encode([]rune(key)), value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJSONInvalid, err)
+	}
+	// keys are sorted by their UTF-16 code units
+	slices.SortFunc(members, func(a, b member) int {
+		return slices.Compare(a.key16, b.key16)
+	})
+	b = append(b, '{')
+	for i, m := range members {
+		if i > 0 {
+			if m.key == members[i-1].key {
+				return nil, fmt.Errorf("%w: duplicate object key %q", ErrJSONInvalid, m.key)
+			}
+			b = append(b, ',')
+		}
+		b = jcsString(b, m.key)
+		b = append(b, ':')
+		b = append(b, m.value...)
+	}
+	return append(b, '}'), nil
+}
+
+// jcsNumber appends the number using the ECMAScript Number serialization required by JCS.
+func jcsNumber(b []byte, n json.Number) ([]byte, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("%w: unsupported number %s", ErrJSONInvalid, n)
+	}
+	if f == 0 {
+		// includes negative zero
+		return append(b, '0'), nil
+	}
+	abs := math.Abs(f)
+	if abs >= 1e-6 && abs < 1e21 {
+		return strconv.AppendFloat(b, f, 'f', -1, 64), nil
+	}
+	// exponent notation without leading zeros in the exponent, e.g. 1e+21 and 1e-7
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	sign, digits := exp[:1], strings.TrimLeft(exp[1:], "0")
+	b = append(b, mantissa...)
+	b = append(b, 'e')
+	b = append(b, sign...)
+	return append(b, digits...), nil
+}
+
+// jcsString appends the quoted string with the minimal escaping required by JCS.
+func jcsString(b []byte, s string) []byte {
+	b = append(b, '"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b = append(b, '\\', '"')
+		case '\\':
+			b = append(b, '\\', '\\')
+		case '\b':
+			b = append(b, '\\', 'b')
+		case '\f':
+			b = append(b, '\\', 'f')
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		case '\t':
+			b = append(b, '\\', 't')
+		default:
+			if r < 0x20 {
+				b = fmt.Appendf(b, `\u%04x`, r)
+			} else {
+				b = utf8.AppendRune(b, r)
+			}
+		}
+	}
+	return append(b, '"')
+}
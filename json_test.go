@@ -0,0 +1,187 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tt := []struct {
+		name   string
+		in     string
+		expect string
+		err    error
+	}{
+		{
+			name:   "empty-object",
+			in:     " { } ",
+			expect: "{}",
+		},
+		{
+			name:   "whitespace-and-order",
+			in:     "{\n  \"b\": [1, 2, {\"d\": true, \"c\": null}],\n  \"a\": \"x\"\n}",
+			expect: `{"a":"x","b":[1,2,{"c":null,"d":true}]}`,
+		},
+		{
+			// RFC 8785 section 3.2.2
+			name:   "rfc8785-example",
+			in:     `{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "literals": [null, true, false]}`,
+			expect: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			// RFC 8785 section 3.2.3
+			name:   "rfc8785-sorting",
+			in:     `{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh", "1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+			expect: "{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"ö\":\"Latin Small Letter O With Diaeresis\",\"€\":\"Euro Sign\",\"😀\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+		},
+		{
+			name:   "numbers",
+			in:     `[0, -0, 1, -1, 1.5, 100, 1e21, 1e20, 0.000001, 0.0000001, 123456789012345680000, -5e-324, 1.7976931348623157e308]`,
+			expect: `[0,0,1,-1,1.5,100,1e+21,100000000000000000000,0.000001,1e-7,123456789012345680000,-5e-324,1.7976931348623157e+308]`,
+		},
+		{
+			name:   "html-not-escaped",
+			in:     `"<a>&\u2028"`,
+			expect: "\"<a>&\u2028\"",
+		},
+		{
+			name: "invalid",
+			in:   `{"a":}`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "trailing-data",
+			in:   `{} {}`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "duplicate-key",
+			in:   `{"a":1,"a":2}`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "lone-high-surrogate",
+			in:   `"\ud800"`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "lone-high-surrogate-max",
+			in:   `"\udbff"`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "lone-low-surrogate",
+			in:   `{"\udc00":1}`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "high-surrogate-without-low",
+			in:   `["\ud83d\u0041"]`,
+			err:  ErrJSONInvalid,
+		},
+		{
+			name: "invalid-utf8",
+			in:   "\"\xff\"",
+			err:  ErrJSONInvalid,
+		},
+		{
+			name:   "escaped-backslash-before-u",
+			in:     `"\\ud800"`,
+			expect: `"\\ud800"`,
+		},
+		{
+			name: "number-overflow",
+			in:   `1e400`,
+			err:  ErrJSONInvalid,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := CanonicalJSON([]byte(tc.in))
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if string(out) != tc.expect {
+				t.Errorf("expected %s, received %s", tc.expect, string(out))
+			}
+		})
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	spaced := json.RawMessage(`{ }`)
+	d, err := FromJSON(spaced)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// byte exact digest of the input
+	expect, _ := SHA256.FromBytes(spaced)
+	if !d.Equal(expect) {
+		t.Errorf("expected %s, received %s", expect.String(), d.String())
+	}
+	if _, err := FromJSON(json.RawMessage(`{`)); !errors.Is(err, ErrJSONInvalid) {
+		t.Errorf("expected err %v, received %v", ErrJSONInvalid, err)
+	}
+
+	// canonical digest of the same content
+	emptyJSON := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	d, err = FromJSONCanonical(spaced)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !d.Equal(emptyJSON) {
+		t.Errorf("expected %s, received %s", emptyJSON.String(), d.String())
+	}
+	d, err = FromJSONCanonical(map[string]any{})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !d.Equal(emptyJSON) {
+		t.Errorf("expected %s, received %s", emptyJSON.String(), d.String())
+	}
+
+	// struct field order does not change the canonical digest
+	type ab struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	type ba struct {
+		B int `json:"b"`
+		A int `json:"a"`
+	}
+	d1, err := SHA512.FromJSONCanonical(ab{A: 1, B: 2})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	d2, err := SHA512.FromJSONCanonical(ba{A: 1, B: 2})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !d1.Equal(d2) || d1.Algorithm() != SHA512 {
+		t.Errorf("canonical digests did not match, %s, %s", d1.String(), d2.String())
+	}
+	if _, err := FromJSONCanonical(func() {}); !errors.Is(err, ErrJSONInvalid) {
+		t.Errorf("expected err %v, received %v", ErrJSONInvalid, err)
+	}
+}
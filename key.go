@@ -0,0 +1,142 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import "fmt"
+
+// KeyMaxSize is the largest hash sum in bytes that can be stored in a [Key].
+const KeyMaxSize = 64
+
+// Key is a compact representation of a [Digest] containing an algorithm identifier and the raw hash sum.
+// Keys are comparable with == and may be used directly as a map key.
+// The algorithm identifiers are assigned by the [Registry] of the algorithm and remain valid for the life of that registry.
+// A key for a custom algorithm from a registry other than the default registry references that registry, keeping it in memory while the key is in use.
+// The zero value corresponds to the zero value [Digest].
+type Key struct {
+	reg *Registry // reg is nil for the default registry
+	alg uint32
+	n   uint8
+	sum [KeyMaxSize]byte
+}
+
+// keyID returns the identifier of the algorithm name in the registry, adding it if needed.
+func (r *Registry) keyID(name string) uint32 {
+	r.mu.RLock()
+	id, ok := r.keyIDs[name]
+	r.mu.RUnlock()
+	if ok {
+		return id
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id, ok := r.keyIDs[name]; ok {
+		return id
+	}
+	if r.keyIDs == nil {
+		r.keyIDs = map[string]uint32{}
+		r.keyNames = []string{""}
+	}
+	id = uint32(len(r.keyNames))
+	r.keyNames = append(r.keyNames, name)
+	r.keyIDs[name] = id
+	return id
+}
+
+// keyName returns the algorithm name for an identifier, or an empty string if the identifier is unknown.
+func (r *Registry) keyName(id uint32) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if int(id) >= len(r.keyNames) {
+		return ""
+	}
+	return r.keyNames[id]
+}
+
+// Key converts the digest to a [Key].
+// The zero value digest returns the zero value key.
+// This will fail if the encoder for the algorithm does not implement [Decoder],
+// or the hash sum is larger than [KeyMaxSize].
+func (d Digest) Key() (Key, error) {
+	if d.IsZero() {
+		return Key{}, nil
+	}
+	sum, err := d.Bytes()
+	if err != nil {
+		return Key{}, err
+	}
+	if len(sum) > KeyMaxSize {
+		return Key{}, fmt.Errorf("%w: hash sum of %d bytes exceeds %d", ErrKeyUnsupported, len(sum), KeyMaxSize)
+	}
+	a := d.Algorithm()
+	k := Key{
+		reg: a.reg,
+		alg: a.registry().keyID(a.name),
+		n:   uint8(len(sum)),
+	}
+	copy(k.sum[:], sum)
+	return k, nil
+}
+
+// Algorithm returns the [Algorithm] portion of the key.
+func (k Key) Algorithm() Algorithm {
+	if k.alg == 0 {
+		return Algorithm{}
+	}
+	a := Algorithm{reg: k.reg}
+	a.name = a.registry().keyName(k.alg)
+	if a.name == "" {
+		return Algorithm{}
+	}
+	return a
+}
+
+// Bytes returns a copy of the hash sum.
+func (k Key) Bytes() []byte {
+	return append([]byte{}, k.sum[:k.n]...)
+}
+
+// Digest converts the key back to a [Digest] using the registered [Encoder].
+// The zero value key returns the zero value digest.
+func (k Key) Digest() (Digest, error) {
+	if k.IsZero() {
+		return Digest{}, nil
+	}
+	alg := k.Algorithm()
+	enc, err := alg.Encode(k.sum[:k.n])
+	if err != nil {
+		return Digest{}, err
+	}
+	return Digest{
+		alg: alg.name,
+		enc: enc,
+		reg: alg.reg,
+	}, nil
+}
+
+// IsZero returns true if the key is the zero value.
+func (k Key) IsZero() bool {
+	return k == Key{}
+}
+
+// String returns the string encoding of the digest for the key.
+// If the key cannot be converted to a digest, an empty string is returned.
+func (k Key) String() string {
+	d, err := k.Digest()
+	if err != nil {
+		return ""
+	}
+	return d.String()
+}
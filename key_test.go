@@ -0,0 +1,184 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"testing"
+)
+
+// largeHash is a [hash.Hash] with an output larger than [KeyMaxSize].
+type largeHash struct{}
+
+func (largeHash) Write(p []byte) (int, error) { return len(p), nil }
+func (largeHash) Sum(b []byte) []byte         { return append(b, make([]byte, KeyMaxSize+1)...) }
+func (largeHash) Reset()                      {}
+func (largeHash) Size() int                   { return KeyMaxSize + 1 }
+func (largeHash) BlockSize() int              { return 64 }
+
+func TestKey(t *testing.T) {
+	r := NewRegistry()
+	noDecode, err := r.Register("no-decode", encodeNoDecode{hex: EncodeHex{Len: 64}}, sha256.New)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	large, err := r.Register("large", EncodeHex{Len: (KeyMaxSize + 1) * 2}, func() hash.Hash { return largeHash{} })
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	custom, err := r.Register("sha256-custom", EncodeHex{Len: 64}, sha256.New)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	tt := []struct {
+		name string
+		d    func() (Digest, error)
+		err  error
+	}{
+		{
+			name: "zero",
+			d:    func() (Digest, error) { return Digest{}, nil },
+		},
+		{
+			name: "sha256",
+			d:    func() (Digest, error) { return SHA256.FromString("{}") },
+		},
+		{
+			name: "sha512",
+			d:    func() (Digest, error) { return SHA512.FromString("{}") },
+		},
+		{
+			name: "custom-registry",
+			d:    func() (Digest, error) { return custom.FromString("{}") },
+		},
+		{
+			name: "no-decode",
+			d:    func() (Digest, error) { return noDecode.FromString("{}") },
+			err:  ErrDecodeUnsupported,
+		},
+		{
+			name: "large",
+			d:    func() (Digest, error) { return large.FromString("{}") },
+			err:  ErrKeyUnsupported,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := tc.d()
+			if err != nil {
+				t.Fatalf("failed to generate digest: %v", err)
+			}
+			k, err := d.Key()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if k.IsZero() != d.IsZero() {
+				t.Errorf("expected IsZero %t, received %t", d.IsZero(), k.IsZero())
+			}
+			if k.String() != d.String() {
+				t.Errorf("expected string %s, received %s", d.String(), k.String())
+			}
+			if !k.Algorithm().Equal(d.Algorithm()) {
+				t.Errorf("expected algorithm %s, received %s", d.Algorithm().String(), k.Algorithm().String())
+			}
+			if !d.IsZero() {
+				sum, err := d.Bytes()
+				if err != nil {
+					t.Fatalf("failed to get bytes: %v", err)
+				}
+				if !bytes.Equal(k.Bytes(), sum) {
+					t.Errorf("expected bytes %x, received %x", sum, k.Bytes())
+				}
+			}
+			out, err := k.Digest()
+			if err != nil {
+				t.Fatalf("failed to convert key to digest: %v", err)
+			}
+			if !out.Equal(d) {
+				t.Errorf("expected digest %s, received %s", d.String(), out.String())
+			}
+			if out.Algorithm().Size() != d.Algorithm().Size() {
+				t.Errorf("registry was not preserved for %s", out.String())
+			}
+			// a separately parsed digest generates an equal key
+			d2, err := d.Algorithm().registry().Parse(d.String())
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			k2, err := d2.Key()
+			if err != nil {
+				t.Fatalf("failed to generate key: %v", err)
+			}
+			if k != k2 {
+				t.Errorf("keys are not equal for %s", d.String())
+			}
+			m := map[Key]bool{k: true}
+			if !m[k2] {
+				t.Errorf("map lookup failed for %s", d.String())
+			}
+		})
+	}
+	t.Run("different", func(t *testing.T) {
+		d1, _ := SHA256.FromString("{}")
+		d2, _ := custom.FromString("{}")
+		d3, _ := SHA256.FromString("")
+		k1, _ := d1.Key()
+		k2, _ := d2.Key()
+		k3, _ := d3.Key()
+		if k1 == k2 {
+			t.Errorf("keys with different algorithms are equal")
+		}
+		if k1 == k3 {
+			t.Errorf("keys with different sums are equal")
+		}
+	})
+	t.Run("registry-table", func(t *testing.T) {
+		// identifiers for custom algorithms are stored on the registry, not in a global table
+		d, _ := custom.FromString("{}")
+		if _, err := d.Key(); err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+		if _, ok := r.keyIDs[custom.name]; !ok {
+			t.Errorf("identifier not stored on the registry")
+		}
+		if _, ok := defaultRegistry.keyIDs[custom.name]; ok {
+			t.Errorf("identifier for a custom registry stored on the default registry")
+		}
+		// the same name in another registry is a different key
+		r2 := NewRegistry()
+		custom2, err := r2.Register("sha256-custom", EncodeHex{Len: 64}, sha256.New)
+		if err != nil {
+			t.Fatalf("failed to register: %v", err)
+		}
+		d2, _ := custom2.FromString("{}")
+		k1, _ := d.Key()
+		k2, _ := d2.Key()
+		if k1 == k2 {
+			t.Errorf("keys from different registries are equal")
+		}
+		if k2.String() != d2.String() {
+			t.Errorf("expected %s, received %s", d2.String(), k2.String())
+		}
+	})
+}
@@ -0,0 +1,270 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+)
+
+// MultiReader is used to calculate digests for multiple algorithms using a single [io.Reader].
+type MultiReader struct {
+	r      io.Reader
+	algs   []Algorithm
+	hashes []hash.Hash
+}
+
+// MultiWriter is used to calculate digests for multiple algorithms with a single writer.
+// It will pass through calls to a [io.Writer] if one is provided.
+// MultiWriter implements [Digester], returning the digest of the first algorithm.
+type MultiWriter struct {
+	w      io.Writer
+	algs   []Algorithm
+	hashes []hash.Hash
+}
+
+// NewMultiReader creates a [MultiReader].
+// If the the reader is not provided, other requests to the returned reader will fail.
+// If no algorithms are provided, the [Canonical] value will be used.
+// This will fail if an algorithm is not registered or is provided more than once.
+func NewMultiReader(r io.Reader, algs ...Algorithm) (MultiReader, error) {
+	retAlgs, retHashes, err := multiHashes(algs)
+	if err != nil {
+		return MultiReader{}, err
+	}
+	return MultiReader{r: r, algs: retAlgs, hashes: retHashes}, nil
+}
+
+// NewMultiWriter creates a [MultiWriter].
+// If the Writer is provided, write calls are passed through while digesting.
+// If no algorithms are provided, the [Canonical] value will be used.
+// This will fail if an algorithm is not registered or is provided more than once.
+func NewMultiWriter(w io.Writer, algs ...Algorithm) (MultiWriter, error) {
+	retAlgs, retHashes, err := multiHashes(algs)
+	if err != nil {
+		return MultiWriter{}, err
+	}
+	return MultiWriter{w: w, algs: retAlgs, hashes: retHashes}, nil
+}
+
+// multiHashes returns a hash for each algorithm, failing on unregistered or duplicate algorithms.
+func multiHashes(algs []Algorithm) ([]Algorithm, []hash.Hash, error) {
+	if len(algs) == 0 {
+		algs = []Algorithm{Canonical}
+	}
+	retAlgs := make([]Algorithm, 0, len(algs))
+	retHashes := make([]hash.Hash, 0, len(algs))
+	for _, alg := range algs {
+		ai, err := alg.info()
+		if err != nil {
+			return nil, nil, err
+		}
+		if slices.ContainsFunc(retAlgs, alg.Equal) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrAlgorithmDuplicate, alg.name)
+		}
+		retAlgs = append(retAlgs, alg)
+		retHashes = append(retHashes, ai.newFn())
+	}
+	return retAlgs, retHashes, nil
+}
+
+func multiDigest(alg Algorithm, algs []Algorithm, hashes []hash.Hash) (Digest, error) {
+	for i := range algs {
+		if algs[i].Equal(alg) {
+			return NewDigest(algs[i], hashes[i])
+		}
+	}
+	return Digest{}, ErrAlgorithmUnknown
+}
+
+func multiDigests(algs []Algorithm, hashes []hash.Hash) ([]Digest, error) {
+	ret := make([]Digest, len(algs))
+	for i := range algs {
+		d, err := NewDigest(algs[i], hashes[i])
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = d
+	}
+	return ret, nil
+}
+
+func multiWrite(hashes []hash.Hash, p []byte) error {
+	errs := []error{}
+	for _, h := range hashes {
+		if _, err := h.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Algorithms returns the list of algorithms being digested.
+func (r MultiReader) Algorithms() []Algorithm {
+	return append([]Algorithm{}, r.algs...)
+}
+
+// Digest returns the current digest value of the first algorithm.
+func (r MultiReader) Digest() (Digest, error) {
+	if len(r.hashes) == 0 {
+		return Digest{}, ErrReaderInvalid
+	}
+	return NewDigest(r.algs[0], r.hashes[0])
+}
+
+// DigestAlgorithm returns the current digest value for the requested algorithm.
+func (r MultiReader) DigestAlgorithm(alg Algorithm) (Digest, error) {
+	if len(r.hashes) == 0 {
+		return Digest{}, ErrReaderInvalid
+	}
+	return multiDigest(alg, r.algs, r.hashes)
+}
+
+// Digests returns the current digest values for every algorithm, in the order the algorithms were provided.
+func (r MultiReader) Digests() ([]Digest, error) {
+	if len(r.hashes) == 0 {
+		return nil, ErrReaderInvalid
+	}
+	return multiDigests(r.algs, r.hashes)
+}
+
+// Hash returns the underlying [hash.Hash] of the first algorithm.
+// Direct writes to this hash will affect the returned digest.
+func (r MultiReader) Hash() hash.Hash {
+	if len(r.hashes) == 0 {
+		return nil
+	}
+	return r.hashes[0]
+}
+
+// Read will pass through the read requests to the underlying reader.
+// All read data is included in the digest computation of every algorithm.
+func (r MultiReader) Read(p []byte) (int, error) {
+	if r.r == nil || len(r.hashes) == 0 {
+		return 0, ErrReaderInvalid
+	}
+	n, err := r.r.Read(p)
+	if n <= 0 {
+		return n, err
+	}
+	hErr := multiWrite(r.hashes, p[:n])
+	if hErr != nil {
+		if err != nil {
+			err = errors.Join(err, hErr)
+		} else {
+			err = hErr
+		}
+	}
+	return n, err
+}
+
+// ReadAll reads everything from the underlying reader, computing the digests, and then discarding the read value.
+func (r MultiReader) ReadAll() error {
+	if r.r == nil || len(r.hashes) == 0 {
+		return ErrReaderInvalid
+	}
+	w := make([]io.Writer, len(r.hashes))
+	for i, h := range r.hashes {
+		w[i] = h
+	}
+	_, err := io.Copy(io.MultiWriter(w...), r.r)
+	return err
+}
+
+// Verify returns true if the compared digest matches the current digest for the same algorithm.
+// Any errors in computing the digest, or an algorithm that was not included, will also return false.
+func (r MultiReader) Verify(cmp Digest) bool {
+	d, err := r.DigestAlgorithm(cmp.Algorithm())
+	if err != nil {
+		return false
+	}
+	return !cmp.IsZero() && d.Equal(cmp)
+}
+
+// Algorithms returns the list of algorithms being digested.
+func (w MultiWriter) Algorithms() []Algorithm {
+	return append([]Algorithm{}, w.algs...)
+}
+
+// Digest returns the digest of the first algorithm for the bytes that have received by Write.
+func (w MultiWriter) Digest() (Digest, error) {
+	if len(w.hashes) == 0 {
+		return Digest{}, ErrWriterInvalid
+	}
+	return NewDigest(w.algs[0], w.hashes[0])
+}
+
+// DigestAlgorithm returns the digest for the requested algorithm.
+func (w MultiWriter) DigestAlgorithm(alg Algorithm) (Digest, error) {
+	if len(w.hashes) == 0 {
+		return Digest{}, ErrWriterInvalid
+	}
+	return multiDigest(alg, w.algs, w.hashes)
+}
+
+// Digests returns the digests for every algorithm, in the order the algorithms were provided.
+func (w MultiWriter) Digests() ([]Digest, error) {
+	if len(w.hashes) == 0 {
+		return nil, ErrWriterInvalid
+	}
+	return multiDigests(w.algs, w.hashes)
+}
+
+// Hash returns the underlying [hash.Hash] of the first algorithm.
+// Direct writes to this hash will affect the returned digest.
+func (w MultiWriter) Hash() hash.Hash {
+	if len(w.hashes) == 0 {
+		return nil
+	}
+	return w.hashes[0]
+}
+
+// Verify returns true if the compared digest matches the current digest for the same algorithm.
+// Any errors in computing the digest, or an algorithm that was not included, will also return false.
+func (w MultiWriter) Verify(cmp Digest) bool {
+	d, err := w.DigestAlgorithm(cmp.Algorithm())
+	if err != nil {
+		return false
+	}
+	return !cmp.IsZero() && d.Equal(cmp)
+}
+
+// Write passes through the bytes to the underlying writer if provided.
+// The processed bytes are then added to the digest of every algorithm.
+func (w MultiWriter) Write(p []byte) (n int, err error) {
+	if len(w.hashes) == 0 {
+		return 0, ErrWriterInvalid
+	}
+	if w.w != nil {
+		n, err = w.w.Write(p)
+	} else {
+		n = len(p)
+	}
+	if n <= 0 {
+		return n, err
+	}
+	hErr := multiWrite(w.hashes, p[:n])
+	if hErr != nil {
+		if err != nil {
+			err = errors.Join(err, hErr)
+		} else {
+			err = hErr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,176 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestMulti(t *testing.T) {
+	sha256JSON := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	sha512JSON := Digest{alg: "sha512", enc: "27c74670adb75075fad058d5ceaf7b20c4e7786c83bae8a32f626f9782af34c9a33c2046ef60fd2a7878d378e29fec851806bbd9a67878f3a9f1cda4830763fd"}
+	sha256Empty := Digest{alg: "sha256", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	tt := []struct {
+		name   string
+		algs   []Algorithm
+		bytes  []byte
+		expect []Digest
+	}{
+		{
+			name:   "canonical",
+			bytes:  []byte("{}"),
+			expect: []Digest{sha256JSON},
+		},
+		{
+			name:   "sha256-sha512",
+			algs:   []Algorithm{SHA256, SHA512},
+			bytes:  []byte("{}"),
+			expect: []Digest{sha256JSON, sha512JSON},
+		},
+		{
+			name:   "sha512-sha256",
+			algs:   []Algorithm{SHA512, SHA256},
+			bytes:  []byte("{}"),
+			expect: []Digest{sha512JSON, sha256JSON},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			check := func(t *testing.T, dr interface {
+				Digest() (Digest, error)
+				Digests() ([]Digest, error)
+				Verify(Digest) bool
+			},
+			) {
+				t.Helper()
+				digs, err := dr.Digests()
+				if err != nil {
+					t.Fatalf("unexpected digests err: %v", err)
+				}
+				if len(digs) != len(tc.expect) {
+					t.Fatalf("expected %d digests, received %d", len(tc.expect), len(digs))
+				}
+				for i := range digs {
+					if !digs[i].Equal(tc.expect[i]) {
+						t.Errorf("expected digest %s, received %s", tc.expect[i].String(), digs[i].String())
+					}
+					if !dr.Verify(tc.expect[i]) {
+						t.Errorf("verify failed for %s", tc.expect[i].String())
+					}
+				}
+				dig, err := dr.Digest()
+				if err != nil {
+					t.Fatalf("unexpected digest err: %v", err)
+				}
+				if !dig.Equal(tc.expect[0]) {
+					t.Errorf("expected digest %s, received %s", tc.expect[0].String(), dig.String())
+				}
+				if dr.Verify(sha256Empty) {
+					t.Errorf("unexpected verify of mismatch")
+				}
+			}
+			t.Run("reader", func(t *testing.T) {
+				r, err := NewMultiReader(bytes.NewReader(tc.bytes), tc.algs...)
+				if err != nil {
+					t.Fatalf("failed to create reader: %v", err)
+				}
+				out, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("unexpected read err: %v", err)
+				}
+				if !bytes.Equal(out, tc.bytes) {
+					t.Errorf("expected bytes %s, received %s", tc.bytes, out)
+				}
+				check(t, r)
+			})
+			t.Run("readall", func(t *testing.T) {
+				r, err := NewMultiReader(bytes.NewReader(tc.bytes), tc.algs...)
+				if err != nil {
+					t.Fatalf("failed to create reader: %v", err)
+				}
+				if err := r.ReadAll(); err != nil {
+					t.Fatalf("unexpected read err: %v", err)
+				}
+				check(t, r)
+			})
+			t.Run("writer", func(t *testing.T) {
+				buf := bytes.Buffer{}
+				w, err := NewMultiWriter(&buf, tc.algs...)
+				if err != nil {
+					t.Fatalf("failed to create writer: %v", err)
+				}
+				if _, err := w.Write(tc.bytes); err != nil {
+					t.Fatalf("unexpected write err: %v", err)
+				}
+				if !bytes.Equal(buf.Bytes(), tc.bytes) {
+					t.Errorf("expected bytes %s, received %s", tc.bytes, buf.Bytes())
+				}
+				check(t, w)
+			})
+		})
+	}
+	t.Run("invalid", func(t *testing.T) {
+		r := MultiReader{}
+		if _, err := r.Read(make([]byte, 10)); !errors.Is(err, ErrReaderInvalid) {
+			t.Errorf("expected err %v, received %v", ErrReaderInvalid, err)
+		}
+		if _, err := r.Digests(); !errors.Is(err, ErrReaderInvalid) {
+			t.Errorf("expected err %v, received %v", ErrReaderInvalid, err)
+		}
+		w := MultiWriter{}
+		if _, err := w.Write([]byte("{}")); !errors.Is(err, ErrWriterInvalid) {
+			t.Errorf("expected err %v, received %v", ErrWriterInvalid, err)
+		}
+		if _, err := w.Digests(); !errors.Is(err, ErrWriterInvalid) {
+			t.Errorf("expected err %v, received %v", ErrWriterInvalid, err)
+		}
+	})
+	t.Run("invalid-algorithms", func(t *testing.T) {
+		tt := []struct {
+			name string
+			algs []Algorithm
+			err  error
+		}{
+			{
+				name: "zero",
+				algs: []Algorithm{{}, SHA512},
+				err:  ErrAlgorithmInvalidName,
+			},
+			{
+				name: "unknown",
+				algs: []Algorithm{SHA256, {name: "unknown"}},
+				err:  ErrAlgorithmUnknown,
+			},
+			{
+				name: "duplicate",
+				algs: []Algorithm{SHA256, SHA512, SHA256},
+				err:  ErrAlgorithmDuplicate,
+			},
+		}
+		for _, tc := range tt {
+			t.Run(tc.name, func(t *testing.T) {
+				if _, err := NewMultiReader(bytes.NewReader([]byte("{}")), tc.algs...); !errors.Is(err, tc.err) {
+					t.Errorf("expected reader err %v, received %v", tc.err, err)
+				}
+				if _, err := NewMultiWriter(nil, tc.algs...); !errors.Is(err, tc.err) {
+					t.Errorf("expected writer err %v, received %v", tc.err, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Result contains the outcome of digesting a single item with [Algorithm.FromFiles] or [Algorithm.FromReaders].
+type Result struct {
+	Digest Digest // Digest is the computed digest, or the zero value on failure.
+	Size   int64  // Size is the number of bytes read.
+	Err    error  // Err is set when the item could not be opened or read.
+}
+
+// ReaderFunc returns a reader for [Algorithm.FromReaders].
+// If the returned reader implements [io.Closer], it is closed after the digest is computed.
+type ReaderFunc func() (io.Reader, error)
+
+// FromFiles generates a [Digest] from the canonical algorithm for each file path.
+// See [Algorithm.FromFiles] for details.
+func FromFiles(ctx context.Context, workers int, paths ...string) ([]Result, error) {
+	return Canonical.FromFiles(ctx, workers, paths...)
+}
+
+// FromReaders generates a [Digest] from the canonical algorithm for each reader.
+// See [Algorithm.FromReaders] for details.
+func FromReaders(ctx context.Context, workers int, fns ...ReaderFunc) ([]Result, error) {
+	return Canonical.FromReaders(ctx, workers, fns...)
+}
+
+// FromFiles generates a [Digest] for each file path using a pool of concurrent workers.
+// See [Algorithm.FromReaders] for the handling of workers, cancellation, and errors.
+func (a Algorithm) FromFiles(ctx context.Context, workers int, paths ...string) ([]Result, error) {
+	fns := make([]ReaderFunc, len(paths))
+	for i, path := range paths {
+		path := path
+		fns[i] = func() (io.Reader, error) {
+			return os.Open(path)
+		}
+	}
+	return a.FromReaders(ctx, workers, fns...)
+}
+
+// FromReaders generates a [Digest] for the content of each reader using a pool of concurrent workers.
+// Each [ReaderFunc] is called by a worker when the item is processed, limiting the number of open readers to the number of workers.
+// A workers value <= 0 uses [runtime.GOMAXPROCS].
+// Cancelling the context stops reading, items being read fail with a [*PartialError], and the remaining items fail with the context error.
+// The results are returned in the input order, and the error joins the errors from every failed item.
+func (a Algorithm) FromReaders(ctx context.Context, workers int, fns ...ReaderFunc) ([]Result, error) {
+	results := make([]Result, len(fns))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(fns) {
+		workers = len(fns)
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(fns) {
+					return
+				}
+				results[i] = a.fromReaderFunc(ctx, fns[i])
+			}
+		}()
+	}
+	wg.Wait()
+	errs := []error{}
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// fromReaderFunc opens and digests a single item for [Algorithm.FromReaders].
+func (a Algorithm) fromReaderFunc(ctx context.Context, fn ReaderFunc) Result {
+	if err := ctx.Err(); err != nil {
+		return Result{Err: err}
+	}
+	if fn == nil {
+		return Result{Err: ErrReaderInvalid}
+	}
+	rd, err := fn()
+	if err != nil {
+		return Result{Err: err}
+	}
+	if rd == nil {
+		return Result{Err: ErrReaderInvalid}
+	}
+	if c, ok := rd.(io.Closer); ok {
+		defer c.Close()
+	}
+	dr, err := a.Digester()
+	if err != nil {
+		return Result{Err: err}
+	}
+	n, err := copyContext(ctx, dr, rd)
+	if err != nil {
+		return Result{Size: n, Err: err}
+	}
+	d, err := dr.Digest()
+	return Result{Digest: d, Size: n, Err: err}
+}
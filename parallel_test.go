@@ -0,0 +1,203 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{}
+	expect := []Result{}
+	for i := 0; i < 20; i++ {
+		content := []byte("file " + strconv.Itoa(i))
+		path := filepath.Join(dir, strconv.Itoa(i))
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		d, err := SHA512.FromBytes(content)
+		if err != nil {
+			t.Fatalf("failed to digest: %v", err)
+		}
+		paths = append(paths, path)
+		expect = append(expect, Result{Digest: d, Size: int64(len(content))})
+	}
+	// include a missing file in the middle of the list
+	missing := 10
+	paths = append(paths[:missing], append([]string{filepath.Join(dir, "missing")}, paths[missing:]...)...)
+	expect = append(expect[:missing], append([]Result{{}}, expect[missing:]...)...)
+	results, err := SHA512.FromFiles(context.Background(), 4, paths...)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not exist error, received %v", err)
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("expected %d results, received %d", len(paths), len(results))
+	}
+	for i, r := range results {
+		if i == missing {
+			if !errors.Is(r.Err, fs.ErrNotExist) {
+				t.Errorf("expected not exist error on the missing file, received %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Errorf("unexpected error on %d: %v", i, r.Err)
+			continue
+		}
+		if !r.Digest.Equal(expect[i].Digest) {
+			t.Errorf("digest mismatch on %d, expected %s, received %s", i, expect[i].Digest.String(), r.Digest.String())
+		}
+		if r.Size != expect[i].Size {
+			t.Errorf("size mismatch on %d, expected %d, received %d", i, expect[i].Size, r.Size)
+		}
+	}
+	// canonical algorithm with the default number of workers
+	results, err = FromFiles(context.Background(), 0, paths[0])
+	if err != nil || len(results) != 1 {
+		t.Fatalf("unexpected results: %v, %v", results, err)
+	}
+	if results[0].Digest.Algorithm() != Canonical {
+		t.Errorf("expected the canonical algorithm, received %s", results[0].Digest.Algorithm().String())
+	}
+}
+
+func TestFromReaders(t *testing.T) {
+	errOpen := errors.New("open failed")
+	t.Run("bounded", func(t *testing.T) {
+		workers := 3
+		var active, maxActive atomic.Int64
+		fns := []ReaderFunc{}
+		expect := []Digest{}
+		for i := 0; i < 30; i++ {
+			content := bytes.Repeat([]byte{byte(i)}, 1024*i)
+			d, err := FromBytes(content)
+			if err != nil {
+				t.Fatalf("failed to digest: %v", err)
+			}
+			expect = append(expect, d)
+			fns = append(fns, func() (io.Reader, error) {
+				cur := active.Add(1)
+				for {
+					prev := maxActive.Load()
+					if cur <= prev || maxActive.CompareAndSwap(prev, cur) {
+						break
+					}
+				}
+				return &closeTracker{Reader: bytes.NewReader(content), active: &active}, nil
+			})
+		}
+		results, err := FromReaders(context.Background(), workers, fns...)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, r := range results {
+			if !r.Digest.Equal(expect[i]) {
+				t.Errorf("digest mismatch on %d, expected %s, received %s", i, expect[i].String(), r.Digest.String())
+			}
+		}
+		if maxActive.Load() > int64(workers) {
+			t.Errorf("expected at most %d active readers, received %d", workers, maxActive.Load())
+		}
+		if active.Load() != 0 {
+			t.Errorf("readers were not closed, %d remain active", active.Load())
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		fns := []ReaderFunc{
+			func() (io.Reader, error) { return bytes.NewReader([]byte("hello")), nil },
+			func() (io.Reader, error) { return nil, errOpen },
+			nil,
+			func() (io.Reader, error) { return nil, nil },
+			func() (io.Reader, error) { return io.MultiReader(bytes.NewReader([]byte("partial")), errReader{}), nil },
+		}
+		results, err := SHA256.FromReaders(context.Background(), 2, fns...)
+		if !errors.Is(err, errOpen) || !errors.Is(err, ErrReaderInvalid) || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected joined errors, received %v", err)
+		}
+		if results[0].Err != nil || results[0].Size != 5 || results[0].Digest.IsZero() {
+			t.Errorf("unexpected result on the valid reader: %v", results[0])
+		}
+		if !errors.Is(results[1].Err, errOpen) {
+			t.Errorf("expected open error, received %v", results[1].Err)
+		}
+		if !errors.Is(results[2].Err, ErrReaderInvalid) || !errors.Is(results[3].Err, ErrReaderInvalid) {
+			t.Errorf("expected invalid reader errors, received %v, %v", results[2].Err, results[3].Err)
+		}
+		if !errors.Is(results[4].Err, io.ErrUnexpectedEOF) || results[4].Size != 7 || !results[4].Digest.IsZero() {
+			t.Errorf("unexpected result on the failed read: %v", results[4])
+		}
+	})
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var opened atomic.Int64
+		fns := []ReaderFunc{}
+		for i := 0; i < 10; i++ {
+			fns = append(fns, func() (io.Reader, error) {
+				if opened.Add(1) == 2 {
+					cancel()
+				}
+				return bytes.NewReader([]byte("hello")), nil
+			})
+		}
+		results, err := FromReaders(ctx, 1, fns...)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected canceled error, received %v", err)
+		}
+		if results[0].Err != nil {
+			t.Errorf("unexpected error on the first reader: %v", results[0].Err)
+		}
+		for i, r := range results[1:] {
+			if !errors.Is(r.Err, context.Canceled) {
+				t.Errorf("expected canceled error on %d, received %v", i+1, r.Err)
+			}
+		}
+		if opened.Load() != 2 {
+			t.Errorf("expected 2 readers to be opened, received %d", opened.Load())
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		results, err := FromReaders(context.Background(), 4)
+		if err != nil || len(results) != 0 {
+			t.Errorf("unexpected results: %v, %v", results, err)
+		}
+	})
+}
+
+type closeTracker struct {
+	io.Reader
+	active *atomic.Int64
+}
+
+func (ct *closeTracker) Close() error {
+	ct.active.Add(-1)
+	return nil
+}
+
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,61 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import "sync/atomic"
+
+// ProgressFunc receives the cumulative number of bytes added to the digest by a [Reader] or [Writer].
+// It is called from the goroutine performing the read or write and should return quickly.
+type ProgressFunc func(total int64)
+
+// counter tracks the bytes added to the digest and reports the progress.
+type counter struct {
+	total    atomic.Int64
+	interval int64
+	reported int64
+	fn       ProgressFunc
+}
+
+// add includes n bytes in the total, calling the progress func after each interval.
+func (c *counter) add(n int64) {
+	if c == nil || n <= 0 {
+		return
+	}
+	total := c.total.Add(n)
+	if c.fn != nil && total-c.reported >= c.interval {
+		c.reported = total
+		c.fn(total)
+	}
+}
+
+// flush calls the progress func if there are bytes that have not been reported.
+func (c *counter) flush() {
+	if c == nil || c.fn == nil {
+		return
+	}
+	total := c.total.Load()
+	if total != c.reported {
+		c.reported = total
+		c.fn(total)
+	}
+}
+
+// load returns the total bytes.
+func (c *counter) load() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.total.Load()
+}
@@ -0,0 +1,154 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestReaderProgress(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	tt := []struct {
+		name     string
+		chunk    int
+		interval int64
+		readAll  bool
+		expect   []int64
+	}{
+		{
+			name:     "every-read",
+			chunk:    30,
+			interval: 0,
+			expect:   []int64{30, 60, 90, 100},
+		},
+		{
+			name:     "interval",
+			chunk:    10,
+			interval: 25,
+			expect:   []int64{30, 60, 90, 100},
+		},
+		{
+			name:     "interval-aligned",
+			chunk:    10,
+			interval: 50,
+			expect:   []int64{50, 100},
+		},
+		{
+			name:     "read-all",
+			chunk:    10,
+			interval: 40,
+			readAll:  true,
+			expect:   []int64{40, 80, 100},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			reported := []int64{}
+			cr := &cancelReader{r: bytes.NewReader(data), chunk: tc.chunk, cancel: func() {}}
+			r := NewReaderProgress(cr, Canonical, tc.interval, func(total int64) {
+				reported = append(reported, total)
+			})
+			var err error
+			if tc.readAll {
+				err = r.ReadAllContext(context.Background())
+			} else {
+				_, err = io.ReadAll(r)
+			}
+			if err != nil {
+				t.Fatalf("failed to read: %v", err)
+			}
+			if !slices.Equal(reported, tc.expect) {
+				t.Errorf("expected progress %v, received %v", tc.expect, reported)
+			}
+			if r.BytesRead() != int64(len(data)) {
+				t.Errorf("expected %d bytes read, received %d", len(data), r.BytesRead())
+			}
+			expect, err := FromBytes(data)
+			if err != nil {
+				t.Fatalf("failed to digest: %v", err)
+			}
+			if !r.Verify(expect) {
+				t.Errorf("digest mismatch")
+			}
+		})
+	}
+	t.Run("without-progress", func(t *testing.T) {
+		r := NewReader(bytes.NewReader(data), Canonical)
+		if err := r.ReadAll(); err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if r.BytesRead() != int64(len(data)) {
+			t.Errorf("expected %d bytes read, received %d", len(data), r.BytesRead())
+		}
+		if (Reader{}).BytesRead() != 0 {
+			t.Errorf("expected 0 bytes read on the zero value")
+		}
+	})
+}
+
+func TestWriterProgress(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	reported := []int64{}
+	buf := &bytes.Buffer{}
+	w := NewWriterProgress(buf, SHA512, 25, func(total int64) {
+		reported = append(reported, total)
+	})
+	for i := 0; i < len(data); i += 10 {
+		if _, err := w.Write(data[i : i+10]); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+	expectReported := []int64{30, 60, 90}
+	if !slices.Equal(reported, expectReported) {
+		t.Errorf("expected progress %v, received %v", expectReported, reported)
+	}
+	// flush reports the final total once
+	w.Flush()
+	w.Flush()
+	expectReported = []int64{30, 60, 90, 100}
+	if !slices.Equal(reported, expectReported) {
+		t.Errorf("expected progress after flush %v, received %v", expectReported, reported)
+	}
+	if w.BytesWritten() != int64(len(data)) {
+		t.Errorf("expected %d bytes written, received %d", len(data), w.BytesWritten())
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("pass through data mismatch")
+	}
+	expect, err := SHA512.FromBytes(data)
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	if !w.Verify(expect) {
+		t.Errorf("digest mismatch")
+	}
+	(Writer{}).Flush()
+	if (Writer{}).BytesWritten() != 0 {
+		t.Errorf("expected 0 bytes written on the zero value")
+	}
+	// copies share the count
+	w2 := NewWriter(nil, Canonical)
+	w2c := w2
+	if _, err := w2c.Write(data); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if w2.BytesWritten() != int64(len(data)) {
+		t.Errorf("expected %d bytes written on the copy, received %d", len(data), w2.BytesWritten())
+	}
+}
@@ -15,6 +15,7 @@
 package digest
 
 import (
+	"context"
 	"errors"
 	"hash"
 	"io"
@@ -22,20 +23,50 @@ import (
 
 // Reader is used to calculate a digest using a [io.Reader].
 type Reader struct {
-	r    io.Reader
-	alg  Algorithm
-	hash hash.Hash
+	r     io.Reader
+	alg   Algorithm
+	hash  hash.Hash
+	count *counter
 }
 
 // NewReader creates a [Reader].
 // If the the reader is not provided, other requests to the returned reader will fail.
 // If Algorithm is the zero value, the [Canonical] value will be used.
 func NewReader(r io.Reader, alg Algorithm) Reader {
+	return newReader(r, alg, &counter{})
+}
+
+// NewReaderProgress creates a [Reader] that reports the cumulative bytes read to the [ProgressFunc].
+// The func is called each time at least interval bytes have been read since the previous call,
+// and once more when the underlying reader returns [io.EOF].
+// An interval <= 0 calls the func after every read.
+func NewReaderProgress(r io.Reader, alg Algorithm, interval int64, fn ProgressFunc) Reader {
+	return newReader(r, alg, &counter{interval: interval, fn: fn})
+}
+
+// NewReaderState creates a [Reader] that resumes the digest from a [State] returned by [Reader.State].
+// The reader must be positioned at the state offset, e.g. by seeking the underlying file, and [Reader.BytesRead] starts at the offset.
+// This will fail if the algorithm is not registered or the hash state cannot be restored.
+func NewReaderState(r io.Reader, s State) (Reader, error) {
+	h, count, err := s.restore()
+	if err != nil {
+		return Reader{}, err
+	}
+	return Reader{
+		r:     r,
+		alg:   s.Algorithm,
+		hash:  h,
+		count: count,
+	}, nil
+}
+
+func newReader(r io.Reader, alg Algorithm, count *counter) Reader {
 	ret := Reader{
-		r:   r,
-		alg: alg,
+		r:     r,
+		alg:   alg,
+		count: count,
 	}
-	ai, _, err := algorithmInfoLookup(alg.name)
+	ai, err := alg.info()
 	if err != nil {
 		ret.alg = Canonical
 		ai = aiCanonical
@@ -44,6 +75,12 @@ func NewReader(r io.Reader, alg Algorithm) Reader {
 	return ret
 }
 
+// BytesRead returns the number of bytes read and added to the digest.
+// Direct writes to the [hash.Hash] are not included.
+func (r Reader) BytesRead() int64 {
+	return r.count.load()
+}
+
 // Digest returns the current digest value.
 func (r Reader) Digest() (Digest, error) {
 	if r.hash == nil {
@@ -66,17 +103,28 @@ func (r Reader) Read(p []byte) (int, error) {
 	}
 	n, err := r.r.Read(p)
 	if n <= 0 {
-		return n, err
+		return n, r.hashRead(nil, err)
 	}
-	_, hErr := r.hash.Write(p[:n])
-	if hErr != nil {
-		if err != nil {
-			err = errors.Join(err, hErr)
-		} else {
-			err = hErr
+	return n, r.hashRead(p[:n], err)
+}
+
+// hashRead adds the bytes returned by a read to the digest, returning the read error joined with any hash error.
+func (r Reader) hashRead(p []byte, err error) error {
+	if len(p) > 0 {
+		_, hErr := r.hash.Write(p)
+		r.count.add(int64(len(p)))
+		if hErr != nil {
+			if err != nil {
+				err = errors.Join(err, hErr)
+			} else {
+				err = hErr
+			}
 		}
 	}
-	return n, err
+	if errors.Is(err, io.EOF) {
+		r.count.flush()
+	}
+	return err
 }
 
 // ReadAll reads everything from the underlying reader, computing the digest, and then discarding the read value.
@@ -84,10 +132,30 @@ func (r Reader) ReadAll() error {
 	if r.r == nil {
 		return ErrReaderInvalid
 	}
-	_, err := io.Copy(r.hash, r.r)
+	_, err := io.Copy(io.Discard, r)
+	return err
+}
+
+// ReadAllContext reads everything from the underlying reader, computing the digest, and then discarding the read value.
+// The context is checked between each chunk read, returning a [*PartialError] with the number of bytes read when the context is done.
+// Every chunk read before stopping is included in the hash, so a later call continues from the same position in the underlying reader.
+func (r Reader) ReadAllContext(ctx context.Context) error {
+	if r.r == nil {
+		return ErrReaderInvalid
+	}
+	_, err := copyContext(ctx, io.Discard, r)
 	return err
 }
 
+// State returns the serializable state of the digest, used to resume with [NewReaderState].
+// This will fail with [ErrStateUnsupported] if the hash for the algorithm does not implement [encoding.BinaryMarshaler].
+func (r Reader) State() (State, error) {
+	if r.hash == nil {
+		return State{}, ErrReaderInvalid
+	}
+	return newState(r.alg, r.hash, r.count.load())
+}
+
 // Verify returns true if the compared digest matches the current digest.
 // Any errors in computing the digest will also return false.
 func (r Reader) Verify(cmp Digest) bool {
@@ -97,3 +165,43 @@ func (r Reader) Verify(cmp Digest) bool {
 	}
 	return !cmp.IsZero() && d.Equal(cmp)
 }
+
+// VerifyConstantTime returns true if the compared digest matches the current digest.
+// The comparison is performed with [Digest.EqualConstantTime].
+// Any errors in computing the digest will also return false.
+func (r Reader) VerifyConstantTime(cmp Digest) bool {
+	d, err := r.Digest()
+	if err != nil {
+		return false
+	}
+	return !cmp.IsZero() && d.EqualConstantTime(cmp)
+}
+
+// copyContext copies from src to dst until EOF, checking the context between each chunk.
+// Each chunk is fully written to dst before the context is checked again.
+func copyContext(ctx context.Context, dst io.Writer, src io.Reader) (int64, error) {
+	buf := make([]byte, 32*1024)
+	var size int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return size, &PartialError{Size: size, Err: err}
+		}
+		n, err := src.Read(buf)
+		if n > 0 {
+			wn, wErr := dst.Write(buf[:n])
+			size += int64(wn)
+			if wErr != nil {
+				return size, wErr
+			}
+			if wn != n {
+				return size, io.ErrShortWrite
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return size, err
+		}
+	}
+}
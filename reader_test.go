@@ -16,6 +16,7 @@ package digest
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"testing"
@@ -99,6 +100,12 @@ func TestReader(t *testing.T) {
 			if tc.errDigest == nil && !tc.r.Verify(tc.expect) {
 				t.Errorf("verify failed")
 			}
+			if tc.r.VerifyConstantTime(tc.mismatch) {
+				t.Errorf("unexpected constant time verify of mismatch")
+			}
+			if tc.errDigest == nil && !tc.r.VerifyConstantTime(tc.expect) {
+				t.Errorf("constant time verify failed")
+			}
 			// access hash directly
 			if tc.errDigest == nil {
 				h := tc.r.Hash()
@@ -189,6 +196,103 @@ func TestReadAll(t *testing.T) {
 			if tc.errDigest == nil && !tc.r.Verify(tc.expect) {
 				t.Errorf("verify failed")
 			}
+			if tc.r.VerifyConstantTime(tc.mismatch) {
+				t.Errorf("unexpected constant time verify of mismatch")
+			}
+			if tc.errDigest == nil && !tc.r.VerifyConstantTime(tc.expect) {
+				t.Errorf("constant time verify failed")
+			}
 		})
 	}
 }
+
+func TestReadAllContext(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	expect, err := FromBytes(data)
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	expectPartial, err := FromBytes(data[:30])
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cr := &cancelReader{r: bytes.NewReader(data), chunk: 10, after: 3, cancel: cancel}
+	r := NewReader(cr, Canonical)
+	err = r.ReadAllContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, received %v", err)
+	}
+	var pErr *PartialError
+	if !errors.As(err, &pErr) || pErr.Size != 30 {
+		t.Fatalf("expected partial error with 30 bytes, received %v", err)
+	}
+	// the hash includes exactly the bytes that were read
+	if !r.Verify(expectPartial) {
+		t.Errorf("partial digest mismatch")
+	}
+	// continue reading with a new context
+	err = r.ReadAllContext(context.Background())
+	if err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	if !r.Verify(expect) {
+		t.Errorf("digest mismatch after resume")
+	}
+	// a done context does not read
+	r = NewReader(bytes.NewReader(data), Canonical)
+	err = r.ReadAllContext(ctx)
+	if !errors.As(err, &pErr) || pErr.Size != 0 {
+		t.Errorf("expected partial error with 0 bytes, received %v", err)
+	}
+	// read errors are returned directly
+	r = NewReader(io.MultiReader(bytes.NewReader(data), errReader{}), Canonical)
+	err = r.ReadAllContext(context.Background())
+	if !errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &pErr) {
+		t.Errorf("expected read error, received %v", err)
+	}
+	// invalid reader
+	err = Reader{}.ReadAllContext(context.Background())
+	if !errors.Is(err, ErrReaderInvalid) {
+		t.Errorf("expected invalid reader, received %v", err)
+	}
+}
+
+// cancelReader returns reads of the chunk size and cancels the context after the requested number of reads.
+type cancelReader struct {
+	r      io.Reader
+	chunk  int
+	after  int
+	reads  int
+	cancel func()
+}
+
+func (cr *cancelReader) Read(p []byte) (int, error) {
+	if len(p) > cr.chunk {
+		p = p[:cr.chunk]
+	}
+	cr.reads++
+	if cr.reads == cr.after {
+		cr.cancel()
+	}
+	return cr.r.Read(p)
+}
+
+func TestReaderNegativeCount(t *testing.T) {
+	r := NewReader(negativeReader{}, Canonical)
+	n, err := r.Read(make([]byte, 10))
+	if n != -1 || !errors.Is(err, io.ErrNoProgress) {
+		t.Errorf("expected -1 and the reader error, received %d, %v", n, err)
+	}
+	if r.BytesRead() != 0 {
+		t.Errorf("expected 0 bytes read, received %d", r.BytesRead())
+	}
+}
+
+// negativeReader returns an invalid negative count.
+type negativeReader struct{}
+
+func (negativeReader) Read(p []byte) (int, error) {
+	return -1, io.ErrNoProgress
+}
@@ -0,0 +1,188 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Registry contains a set of registered algorithms.
+// The package level functions use a default registry.
+// Separate registries may be created to isolate custom algorithms, e.g. for tests and plugins.
+// Every registry includes the OCI registered algorithms, [SHA256] and [SHA512], which cannot be removed.
+// The zero value is ready to use.
+type Registry struct {
+	mu         sync.RWMutex
+	algorithms map[string]algorithmInfo
+	keyNames   []string          // keyNames maps a [Key] algorithm identifier to the name, index 0 is the zero value
+	keyIDs     map[string]uint32 // keyIDs maps an algorithm name to the [Key] identifier
+}
+
+var defaultRegistry = &Registry{}
+
+// NewRegistry returns a new [Registry] containing only the OCI registered algorithms.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// DefaultRegistry returns the [Registry] used by the package level functions.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// List returns the algorithms in the registry, sorted by name.
+func (r *Registry) List() []Algorithm {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.initLocked()
+
+	ret := make([]Algorithm, 0, len(r.algorithms))
+	for name := range r.algorithms {
+		ret = append(ret, r.algorithm(name))
+	}
+	slices.SortFunc(ret, Algorithm.Compare)
+	return ret
+}
+
+// ListDetails returns the metadata for each algorithm in the registry, sorted by name.
+func (r *Registry) ListDetails() []AlgorithmDetails {
+	list := r.List()
+	ret := make([]AlgorithmDetails, 0, len(list))
+	for _, a := range list {
+		// skip algorithms removed after the list was generated
+		if d, err := a.Details(); err == nil {
+			ret = append(ret, d)
+		}
+	}
+	return ret
+}
+
+// Lookup is used to get a previously registered [Algorithm].
+func (r *Registry) Lookup(name string) (Algorithm, error) {
+	_, a, err := r.infoLookup(name)
+	return a, err
+}
+
+// Parse validates the string representation of a [Digest] using the algorithms in the registry.
+// An empty string will not fail but will return an empty [Digest].
+// This will fail if the string does not match the [DigestRegexp] requirements,
+// the algorithm was not already registered, or the encoding does not match the algorithm requirements.
+func (r *Registry) Parse(s string) (Digest, error) {
+	if s == "" {
+		return Digest{}, nil
+	}
+	algPart, encPart, ok := strings.Cut(s, ":")
+	if !ok {
+		return Digest{}, fmt.Errorf("%w: %s", ErrDigestInvalid, s)
+	}
+	ai, a, err := r.infoLookup(algPart)
+	if err != nil {
+		return Digest{}, err
+	}
+	if ai.enc == nil || !ai.enc.Validate(encPart) {
+		return Digest{}, fmt.Errorf("%w: %s", ErrEncodingInvalid, encPart)
+	}
+	return Digest{
+		alg: algPart,
+		enc: encPart,
+		reg: a.reg,
+	}, nil
+}
+
+// Register is used to register new hash algorithms.
+// Attempting to register an already registered algorithm will fail.
+// The name must follow the regexp "[a-z0-9]+([+._-][a-z0-9]+)*".
+// The encoder and hash function are also verified to be valid interfaces.
+func (r *Registry) Register(name string, enc Encoder, newFn func() hash.Hash) (Algorithm, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.initLocked()
+
+	if _, ok := r.algorithms[name]; ok {
+		return Algorithm{}, fmt.Errorf("%w: %s", ErrAlgorithmExists, name)
+	}
+	ai, err := newAlgorithmInfo(name, enc, newFn)
+	if err != nil {
+		return Algorithm{}, err
+	}
+	r.algorithms[name] = ai
+	return r.algorithm(name), nil
+}
+
+// Unregister removes a previously registered algorithm.
+// The OCI registered algorithms cannot be removed.
+// Existing [Algorithm] and [Digest] values using the removed algorithm will fail on future lookups.
+func (r *Registry) Unregister(name string) error {
+	switch name {
+	case SHA256.name, SHA512.name:
+		return fmt.Errorf("%w: %s", ErrAlgorithmReserved, name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.initLocked()
+
+	if _, ok := r.algorithms[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrAlgorithmUnknown, name)
+	}
+	delete(r.algorithms, name)
+	return nil
+}
+
+// algorithm returns an [Algorithm] referencing this registry.
+// The OCI registered algorithms are the same in every registry and do not reference the registry,
+// so they compare with == to [SHA256] and [SHA512].
+func (r *Registry) algorithm(name string) Algorithm {
+	if r == defaultRegistry || name == SHA256.name || name == SHA512.name {
+		return Algorithm{name: name}
+	}
+	return Algorithm{name: name, reg: r}
+}
+
+func (r *Registry) infoLookup(name string) (algorithmInfo, Algorithm, error) {
+	// skip the lock for registered algorithms
+	switch name {
+	case "sha256":
+		return aiSHA256, SHA256, nil
+	case "sha512":
+		return aiSHA512, SHA512, nil
+	case "":
+		return algorithmInfo{}, Algorithm{}, ErrAlgorithmInvalidName
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if a, ok := r.algorithms[name]; ok {
+		return a, r.algorithm(a.name), nil
+	}
+	return algorithmInfo{}, Algorithm{}, fmt.Errorf("%w: %s", ErrAlgorithmUnknown, name)
+}
+
+// initLocked adds the OCI registered algorithms to a new registry.
+// The write lock must be held by the caller.
+func (r *Registry) initLocked() {
+	if r.algorithms != nil {
+		return
+	}
+	r.algorithms = map[string]algorithmInfo{
+		aiSHA256.name: aiSHA256,
+		aiSHA512.name: aiSHA512,
+	}
+}
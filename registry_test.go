@@ -0,0 +1,179 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"crypto/sha512"
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	// default algorithms are included
+	list := r.List()
+	if len(list) != 2 || list[0].name != "sha256" || list[1].name != "sha512" {
+		t.Fatalf("unexpected initial list: %v", list)
+	}
+	if _, err := r.Register("sha256", EncodeHex{Len: 64}, sha512.New512_256); !errors.Is(err, ErrAlgorithmExists) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmExists, err)
+	}
+	// register an algorithm only in this registry
+	a, err := r.Register("registry-test", EncodeHex{Len: 64}, sha512.New512_256)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if _, err := r.Register("registry-test", EncodeHex{Len: 64}, sha512.New512_256); !errors.Is(err, ErrAlgorithmExists) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmExists, err)
+	}
+	if _, err := r.Register("invalid*name", EncodeHex{Len: 64}, sha512.New512_256); !errors.Is(err, ErrAlgorithmInvalidName) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmInvalidName, err)
+	}
+	if _, err := AlgorithmLookup("registry-test"); !errors.Is(err, ErrAlgorithmUnknown) {
+		t.Errorf("algorithm leaked into the default registry: %v", err)
+	}
+	lookup, err := r.Lookup("registry-test")
+	if err != nil {
+		t.Fatalf("failed to lookup: %v", err)
+	}
+	if !lookup.Equal(a) {
+		t.Errorf("lookup mismatch, expected %s, received %s", a.String(), lookup.String())
+	}
+	list = r.List()
+	if len(list) != 3 || list[0].name != "registry-test" {
+		t.Errorf("unexpected list: %v", list)
+	}
+	details := r.ListDetails()
+	if len(details) != 3 {
+		t.Fatalf("unexpected details: %v", details)
+	}
+	if details[0].OCI || details[0].Size != 32 || !details[0].Algorithm.Equal(a) {
+		t.Errorf("unexpected details for custom algorithm: %v", details[0])
+	}
+	if !details[1].OCI || !details[2].OCI {
+		t.Errorf("unexpected details for OCI algorithms: %v", details[1:])
+	}
+	// algorithm methods use the registry
+	if a.Size() != 32 {
+		t.Errorf("unexpected size %d", a.Size())
+	}
+	d, err := a.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	expect := "registry-test:a6202e7635867740d52b081ffef7d187d78aa51e316ef7450de56f916e60eeb8"
+	if d.String() != expect {
+		t.Errorf("expected %s, received %s", expect, d.String())
+	}
+	if d.Algorithm().Size() != 32 {
+		t.Errorf("digest algorithm did not reference the registry")
+	}
+	// parse is scoped to the registry
+	parsed, err := r.Parse(expect)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if !parsed.Equal(d) {
+		t.Errorf("parse mismatch, expected %s, received %s", d.String(), parsed.String())
+	}
+	if _, err := Parse(expect); !errors.Is(err, ErrAlgorithmUnknown) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmUnknown, err)
+	}
+	if _, err := r.Parse("sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"); err != nil {
+		t.Errorf("failed to parse sha256: %v", err)
+	}
+	// unregister
+	if err := r.Unregister("sha256"); !errors.Is(err, ErrAlgorithmReserved) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmReserved, err)
+	}
+	if err := r.Unregister("registry-test"); err != nil {
+		t.Errorf("failed to unregister: %v", err)
+	}
+	if err := r.Unregister("registry-test"); !errors.Is(err, ErrAlgorithmUnknown) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmUnknown, err)
+	}
+	if _, err := r.Lookup("registry-test"); !errors.Is(err, ErrAlgorithmUnknown) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmUnknown, err)
+	}
+	if _, err := r.Parse(expect); !errors.Is(err, ErrAlgorithmUnknown) {
+		t.Errorf("expected err %v, received %v", ErrAlgorithmUnknown, err)
+	}
+}
+
+func TestRegistryZero(t *testing.T) {
+	r := Registry{}
+	if _, err := r.Lookup("sha512"); err != nil {
+		t.Errorf("failed to lookup sha512: %v", err)
+	}
+	if list := r.List(); len(list) != 2 {
+		t.Errorf("unexpected list: %v", list)
+	}
+	if _, err := r.Register("registry-zero", EncodeHex{Len: 64}, sha512.New512_256); err != nil {
+		t.Errorf("failed to register: %v", err)
+	}
+}
+
+func TestRegistryComparable(t *testing.T) {
+	r := NewRegistry()
+	// OCI registered algorithms are comparable across registries
+	a, err := r.Lookup("sha256")
+	if err != nil {
+		t.Fatalf("failed to lookup: %v", err)
+	}
+	if a != SHA256 {
+		t.Errorf("sha256 from a new registry is not == to SHA256")
+	}
+	if list := r.List(); list[0] != SHA256 || list[1] != SHA512 {
+		t.Errorf("listed algorithms are not == to the package values: %v", list)
+	}
+	d1, err := r.Parse("sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	d2, err := Parse(d1.String())
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if d1 != d2 {
+		t.Errorf("sha256 digests from different registries are not ==")
+	}
+	// custom algorithms reference the registry, only Equal matches by name
+	r2 := NewRegistry()
+	c1, err := r.Register("sha512-256", EncodeHex{Len: 64}, sha512.New512_256)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	c2, err := r2.Register("sha512-256", EncodeHex{Len: 64}, sha512.New512_256)
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	if c1 == c2 {
+		t.Errorf("custom algorithms from different registries are ==")
+	}
+	if !c1.Equal(c2) {
+		t.Errorf("custom algorithms from different registries are not Equal")
+	}
+	cd1, err := c1.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	cd2, err := c2.FromString("{}")
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	if cd1 == cd2 || !cd1.Equal(cd2) {
+		t.Errorf("unexpected comparison of custom digests from different registries")
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Set contains a unique collection of digests with support for looking up a digest by a prefix of the encoded value.
+// It is safe for concurrent use.
+// The zero value is an empty set ready to use.
+type Set struct {
+	mu      sync.RWMutex
+	entries map[string][]Digest // entries are indexed by algorithm name and sorted by the encoded value
+}
+
+// NewSet creates a [Set] containing the provided digests.
+// Zero value digests are ignored.
+func NewSet(digests ...Digest) *Set {
+	s := &Set{}
+	for _, d := range digests {
+		_ = s.Add(d)
+	}
+	return s
+}
+
+// Add includes a digest in the set.
+// Adding a digest that is already in the set has no effect.
+// This will fail if the digest is the zero value.
+func (s *Set) Add(d Digest) error {
+	if d.alg == "" || d.enc == "" {
+		return ErrDigestInvalid
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries == nil {
+		s.entries = map[string][]Digest{}
+	}
+	list := s.entries[d.alg]
+	i, found := slices.BinarySearchFunc(list, d.enc, setCompare)
+	if found {
+		return nil
+	}
+	s.entries[d.alg] = slices.Insert(list, i, d)
+	return nil
+}
+
+// Contains returns true if the digest is in the set.
+func (s *Set) Contains(d Digest) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, found := slices.BinarySearchFunc(s.entries[d.alg], d.enc, setCompare)
+	return found
+}
+
+// Len returns the number of digests in the set.
+func (s *Set) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	n := 0
+	for _, list := range s.entries {
+		n += len(list)
+	}
+	return n
+}
+
+// List returns the digests in the set, sorted by the algorithm name and encoded value.
+func (s *Set) List() []Digest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	algs := make([]string, 0, len(s.entries))
+	for alg := range s.entries {
+		algs = append(algs, alg)
+	}
+	slices.Sort(algs)
+	ret := []Digest{}
+	for _, alg := range algs {
+		ret = append(ret, s.entries[alg]...)
+	}
+	return ret
+}
+
+// Lookup returns the digest with an encoded value beginning with the prefix.
+// If the algorithm is the zero value, digests for every algorithm are searched.
+// An exact match of the encoded value is always returned.
+// This will fail with [ErrDigestNotFound] if no digest matches,
+// or [ErrDigestAmbiguous] if more than one digest matches.
+func (s *Set) Lookup(alg Algorithm, prefix string) (Digest, error) {
+	if prefix == "" {
+		return Digest{}, fmt.Errorf("%w: empty prefix", ErrDigestNotFound)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var algs []string
+	if alg.IsZero() {
+		for name := range s.entries {
+			algs = append(algs, name)
+		}
+	} else {
+		algs = []string{alg.name}
+	}
+	var match Digest
+	count := 0
+	for _, name := range algs {
+		list := s.entries[name]
+		i, found := slices.BinarySearchFunc(list, prefix, setCompare)
+		if found {
+			return list[i], nil
+		}
+		for ; i < len(list) && strings.HasPrefix(list[i].enc, prefix); i++ {
+			match = list[i]
+			count++
+		}
+	}
+	switch count {
+	case 0:
+		return Digest{}, fmt.Errorf("%w: %s", ErrDigestNotFound, prefix)
+	case 1:
+		return match, nil
+	default:
+		return Digest{}, fmt.Errorf("%w: %s matches %d digests", ErrDigestAmbiguous, prefix, count)
+	}
+}
+
+// Remove deletes a digest from the set.
+// Removing a digest that is not in the set has no effect.
+func (s *Set) Remove(d Digest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.entries[d.alg]
+	i, found := slices.BinarySearchFunc(list, d.enc, setCompare)
+	if !found {
+		return
+	}
+	list = slices.Delete(list, i, i+1)
+	if len(list) == 0 {
+		delete(s.entries, d.alg)
+		return
+	}
+	s.entries[d.alg] = list
+}
+
+// ShortLen returns the length of the shortest prefix of the encoded value that uniquely identifies the digest.
+// Uniqueness is considered against the digests of every algorithm,
+// so the prefix can be resolved with [Set.Lookup] using the zero value [Algorithm].
+// This will fail with [ErrDigestNotFound] if the digest is not in the set.
+func (s *Set) ShortLen(d Digest) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, found := slices.BinarySearchFunc(s.entries[d.alg], d.enc, setCompare); !found {
+		return 0, fmt.Errorf("%w: %s", ErrDigestNotFound, d.String())
+	}
+	// the longest common prefix with a neighbor in each sorted list, plus one character
+	n := 0
+	for name, list := range s.entries {
+		i, found := slices.BinarySearchFunc(list, d.enc, setCompare)
+		if i > 0 {
+			n = max(n, commonPrefixLen(list[i-1].enc, d.enc))
+		}
+		if found && name != d.alg {
+			// an identical encoded value with another algorithm
+			n = max(n, len(d.enc))
+		}
+		if found {
+			i++
+		}
+		if i < len(list) {
+			n = max(n, commonPrefixLen(list[i].enc, d.enc))
+		}
+	}
+	return min(n+1, len(d.enc)), nil
+}
+
+func setCompare(d Digest, enc string) int {
+	return strings.Compare(d.enc, enc)
+}
+
+func commonPrefixLen(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
@@ -0,0 +1,219 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	d1 := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	d2 := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8b"}
+	d3 := Digest{alg: "sha256", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	d4 := Digest{alg: "sha512", enc: "27c74670adb75075fad058d5ceaf7b20c4e7786c83bae8a32f626f9782af34c9a33c2046ef60fd2a7878d378e29fec851806bbd9a67878f3a9f1cda4830763fd"}
+	d5 := Digest{alg: "sha512", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	s := NewSet(d1, d2, d3, d4, d5, d1, Digest{})
+	if s.Len() != 5 {
+		t.Errorf("expected 5 entries, received %d", s.Len())
+	}
+	if err := s.Add(Digest{}); !errors.Is(err, ErrDigestInvalid) {
+		t.Errorf("expected err %v, received %v", ErrDigestInvalid, err)
+	}
+	list := s.List()
+	expectList := []Digest{d1, d2, d3, d4, d5}
+	if len(list) != len(expectList) {
+		t.Fatalf("expected list length %d, received %d", len(expectList), len(list))
+	}
+	for i := range list {
+		if !list[i].Equal(expectList[i]) {
+			t.Errorf("list entry %d, expected %s, received %s", i, expectList[i].String(), list[i].String())
+		}
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		tt := []struct {
+			name   string
+			alg    Algorithm
+			prefix string
+			expect Digest
+			err    error
+		}{
+			{
+				name:   "short",
+				alg:    SHA256,
+				prefix: "e3",
+				expect: d3,
+			},
+			{
+				name:   "exact",
+				alg:    SHA256,
+				prefix: d1.enc,
+				expect: d1,
+			},
+			{
+				name:   "ambiguous",
+				alg:    SHA256,
+				prefix: "44136fa3",
+				err:    ErrDigestAmbiguous,
+			},
+			{
+				name:   "not-found",
+				alg:    SHA256,
+				prefix: "27c7",
+				err:    ErrDigestNotFound,
+			},
+			{
+				name:   "empty",
+				alg:    SHA256,
+				prefix: "",
+				err:    ErrDigestNotFound,
+			},
+			{
+				name:   "sha512",
+				alg:    SHA512,
+				prefix: "27c7",
+				expect: d4,
+			},
+			{
+				name:   "any-alg",
+				prefix: "27c7",
+				expect: d4,
+			},
+			{
+				name:   "any-alg-ambiguous",
+				prefix: "e3b0",
+				err:    ErrDigestAmbiguous,
+			},
+		}
+		for _, tc := range tt {
+			t.Run(tc.name, func(t *testing.T) {
+				d, err := s.Lookup(tc.alg, tc.prefix)
+				if tc.err != nil {
+					if !errors.Is(err, tc.err) {
+						t.Errorf("expected err %v, received %v", tc.err, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected err: %v", err)
+				}
+				if !d.Equal(tc.expect) {
+					t.Errorf("expected %s, received %s", tc.expect.String(), d.String())
+				}
+			})
+		}
+	})
+
+	t.Run("short-len", func(t *testing.T) {
+		tt := []struct {
+			name   string
+			d      Digest
+			expect int
+			err    error
+		}{
+			{
+				name:   "neighbor-differs-at-end",
+				d:      d1,
+				expect: 64,
+			},
+			{
+				name:   "prefix-of-other-algorithm",
+				d:      d3,
+				expect: 64,
+			},
+			{
+				name:   "shares-prefix-with-other-algorithm",
+				d:      d5,
+				expect: 65,
+			},
+			{
+				name:   "unique",
+				d:      d4,
+				expect: 1,
+			},
+			{
+				name: "missing",
+				d:    Digest{alg: "sha256", enc: "0000"},
+				err:  ErrDigestNotFound,
+			},
+		}
+		for _, tc := range tt {
+			t.Run(tc.name, func(t *testing.T) {
+				n, err := s.ShortLen(tc.d)
+				if tc.err != nil {
+					if !errors.Is(err, tc.err) {
+						t.Errorf("expected err %v, received %v", tc.err, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected err: %v", err)
+				}
+				if n != tc.expect {
+					t.Errorf("expected %d, received %d", tc.expect, n)
+				}
+				// the short value resolves without the algorithm
+				d, err := s.Lookup(Algorithm{}, tc.d.enc[:n])
+				if err != nil || !d.Equal(tc.d) {
+					t.Errorf("lookup of %s returned %s, err %v", tc.d.enc[:n], d.String(), err)
+				}
+			})
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		s.Remove(d2)
+		s.Remove(d2)
+		if s.Contains(d2) {
+			t.Errorf("digest was not removed")
+		}
+		if !s.Contains(d1) {
+			t.Errorf("digest missing")
+		}
+		n, err := s.ShortLen(d1)
+		if err != nil || n != 1 {
+			t.Errorf("unexpected short length %d, err %v", n, err)
+		}
+		d, err := s.Lookup(SHA256, "44136fa3")
+		if err != nil || !d.Equal(d1) {
+			t.Errorf("unexpected lookup %s, err %v", d.String(), err)
+		}
+	})
+}
+
+func TestSetConcurrent(t *testing.T) {
+	s := Set{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			d, err := SHA256.FromBytes([]byte{byte(i)})
+			if err != nil {
+				t.Errorf("failed to generate digest: %v", err)
+				return
+			}
+			_ = s.Add(d)
+			_, _ = s.ShortLen(d)
+			_, _ = s.Lookup(SHA256, d.enc[:4])
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 10 {
+		t.Errorf("expected 10 entries, received %d", s.Len())
+	}
+}
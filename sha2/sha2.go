@@ -0,0 +1,49 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sha2 registers additional SHA-2 family algorithms with the digest package.
+// Importing this package registers the following algorithms:
+//
+//   - sha384 using [crypto/sha512.New384]
+//   - sha512-256 using [crypto/sha512.New512_256]
+//
+// These algorithms are not registered by the OCI image-spec and may not be supported by other implementations.
+package sha2
+
+import (
+	"crypto/sha512"
+	"hash"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+var (
+	SHA384     digest.Algorithm // SHA384 defines the registered sha384 digester based on [crypto/sha512.New384].
+	SHA512t256 digest.Algorithm // SHA512t256 defines the registered sha512-256 digester based on [crypto/sha512.New512_256].
+)
+
+func init() {
+	SHA384 = register("sha384", digest.EncodeHex{Len: 96}, sha512.New384)
+	SHA512t256 = register("sha512-256", digest.EncodeHex{Len: 64}, sha512.New512_256)
+}
+
+// register adds the algorithm, returning the previously registered algorithm when the name already exists.
+// Errors are ignored to avoid a panic on import, leaving the returned algorithm as the zero value.
+func register(name string, enc digest.Encoder, newFn func() hash.Hash) digest.Algorithm {
+	alg, err := digest.AlgorithmRegister(name, enc, newFn)
+	if err != nil {
+		alg, _ = digest.AlgorithmLookup(name)
+	}
+	return alg
+}
@@ -0,0 +1,78 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sha2
+
+import (
+	"testing"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+func TestAlgorithms(t *testing.T) {
+	tt := []struct {
+		name   string
+		alg    digest.Algorithm
+		in     string
+		expect string
+	}{
+		{
+			name:   "sha384-empty",
+			alg:    SHA384,
+			expect: "sha384:38b060a751ac96384cd9327eb1b1e36a21fdb71114be07434c0cc7bf63f6e1da274edebfe76f65fbd51ad2f14898b95b",
+		},
+		{
+			name:   "sha384-empty-json",
+			alg:    SHA384,
+			in:     "{}",
+			expect: "sha384:d2a23bc783e3aa38f401e13c7488505137c4954a7fd88331f1597c5ff71111dc807c7370a5b282c6da541c56ede69f30",
+		},
+		{
+			name:   "sha512-256-empty",
+			alg:    SHA512t256,
+			expect: "sha512-256:c672b8d1ef56ed28ab87c3622c5114069bdd3ad7b8f9737498d0c01ecef0967a",
+		},
+		{
+			name:   "sha512-256-empty-json",
+			alg:    SHA512t256,
+			in:     "{}",
+			expect: "sha512-256:a6202e7635867740d52b081ffef7d187d78aa51e316ef7450de56f916e60eeb8",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := tc.alg.FromString(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if d.String() != tc.expect {
+				t.Errorf("expected %s, received %s", tc.expect, d.String())
+			}
+			parsed, err := digest.Parse(tc.expect)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if !parsed.Equal(d) {
+				t.Errorf("parsed digest mismatch, expected %s, received %s", d.String(), parsed.String())
+			}
+			lookup, err := digest.AlgorithmLookup(tc.alg.String())
+			if err != nil {
+				t.Fatalf("failed to lookup algorithm: %v", err)
+			}
+			if !lookup.Equal(tc.alg) {
+				t.Errorf("lookup mismatch, expected %s, received %s", tc.alg.String(), lookup.String())
+			}
+		})
+	}
+}
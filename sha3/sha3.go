@@ -0,0 +1,52 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.24
+
+// Package sha3 registers SHA-3 family algorithms with the digest package.
+// Importing this package registers the following algorithms:
+//
+//   - sha3-256 using [crypto/sha3.New256]
+//   - sha3-512 using [crypto/sha3.New512]
+//
+// These algorithms are not registered by the OCI image-spec and may not be supported by other implementations.
+// This package requires Go 1.24 or newer for [crypto/sha3].
+package sha3
+
+import (
+	"crypto/sha3"
+	"hash"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+var (
+	SHA256 digest.Algorithm // SHA256 defines the registered sha3-256 digester based on [crypto/sha3.New256].
+	SHA512 digest.Algorithm // SHA512 defines the registered sha3-512 digester based on [crypto/sha3.New512].
+)
+
+func init() {
+	SHA256 = register("sha3-256", digest.EncodeHex{Len: 64}, func() hash.Hash { return sha3.New256() })
+	SHA512 = register("sha3-512", digest.EncodeHex{Len: 128}, func() hash.Hash { return sha3.New512() })
+}
+
+// register adds the algorithm, returning the previously registered algorithm when the name already exists.
+// Errors are ignored to avoid a panic on import, leaving the returned algorithm as the zero value.
+func register(name string, enc digest.Encoder, newFn func() hash.Hash) digest.Algorithm {
+	alg, err := digest.AlgorithmRegister(name, enc, newFn)
+	if err != nil {
+		alg, _ = digest.AlgorithmLookup(name)
+	}
+	return alg
+}
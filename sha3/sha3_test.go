@@ -0,0 +1,80 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.24
+
+package sha3
+
+import (
+	"testing"
+
+	digest "github.com/sudo-bmitch/oci-digest"
+)
+
+func TestAlgorithms(t *testing.T) {
+	tt := []struct {
+		name   string
+		alg    digest.Algorithm
+		in     string
+		expect string
+	}{
+		{
+			name:   "sha3-256-empty",
+			alg:    SHA256,
+			expect: "sha3-256:a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+		},
+		{
+			name:   "sha3-256-empty-json",
+			alg:    SHA256,
+			in:     "{}",
+			expect: "sha3-256:840eb7aa2a9935de63366bacbe9d97e978a859e93dc792a0334de60ed52f8e99",
+		},
+		{
+			name:   "sha3-512-empty",
+			alg:    SHA512,
+			expect: "sha3-512:a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a615b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26",
+		},
+		{
+			name:   "sha3-512-empty-json",
+			alg:    SHA512,
+			in:     "{}",
+			expect: "sha3-512:c1802e6b9670927ebfddb7f67b3824642237361f07db35526c42c555ffd2dbe74156c366e1550ef8c0508a6cc796409a7194a59bba4d300a6182b483d315a862",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := tc.alg.FromString(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if d.String() != tc.expect {
+				t.Errorf("expected %s, received %s", tc.expect, d.String())
+			}
+			parsed, err := digest.Parse(tc.expect)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if !parsed.Equal(d) {
+				t.Errorf("parsed digest mismatch, expected %s, received %s", d.String(), parsed.String())
+			}
+			lookup, err := digest.AlgorithmLookup(tc.alg.String())
+			if err != nil {
+				t.Fatalf("failed to lookup algorithm: %v", err)
+			}
+			if !lookup.Equal(tc.alg) {
+				t.Errorf("lookup mismatch, expected %s, received %s", tc.alg.String(), lookup.String())
+			}
+		})
+	}
+}
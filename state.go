@@ -0,0 +1,108 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// State contains the serialized state of a [Reader] or [Writer], used to resume a digest, e.g. after a process restart.
+// The hash for the algorithm must implement [encoding.BinaryMarshaler] and [encoding.BinaryUnmarshaler],
+// which includes the hashes from [crypto/sha256] and [crypto/sha512].
+type State struct {
+	Algorithm Algorithm // Algorithm is the algorithm for the digest.
+	Offset    int64     // Offset is the number of bytes included in the hash.
+	Hash      []byte    // Hash is the marshaled state of the [hash.Hash].
+}
+
+// newState returns the state of the hash.
+func newState(alg Algorithm, h hash.Hash, offset int64) (State, error) {
+	m, ok := h.(encoding.BinaryMarshaler)
+	if !ok {
+		return State{}, fmt.Errorf("%w: %s", ErrStateUnsupported, alg.name)
+	}
+	hs, err := m.MarshalBinary()
+	if err != nil {
+		return State{}, fmt.Errorf("%w: %s: %w", ErrStateUnsupported, alg.name, err)
+	}
+	return State{
+		Algorithm: alg,
+		Offset:    offset,
+		Hash:      hs,
+	}, nil
+}
+
+// restore returns a new hash and counter set to the state.
+func (s State) restore() (hash.Hash, *counter, error) {
+	ai, err := s.Algorithm.info()
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.Offset < 0 {
+		return nil, nil, fmt.Errorf("%w: negative offset %d", ErrStateInvalid, s.Offset)
+	}
+	h := ai.newFn()
+	u, ok := h.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrStateUnsupported, s.Algorithm.name)
+	}
+	if err := u.UnmarshalBinary(s.Hash); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %w", ErrStateInvalid, s.Algorithm.name, err)
+	}
+	c := &counter{reported: s.Offset}
+	c.total.Store(s.Offset)
+	return h, c, nil
+}
+
+// MarshalBinary returns the binary encoding of the state.
+// The encoding is the length of the algorithm name as a uvarint, the algorithm name, the offset as a uvarint, and the hash state.
+func (s State) MarshalBinary() ([]byte, error) {
+	if s.Algorithm.IsZero() || s.Offset < 0 {
+		return nil, ErrStateInvalid
+	}
+	b := make([]byte, 0, 2*binary.MaxVarintLen64+len(s.Algorithm.name)+len(s.Hash))
+	b = binary.AppendUvarint(b, uint64(len(s.Algorithm.name)))
+	b = append(b, s.Algorithm.name...)
+	b = binary.AppendUvarint(b, uint64(s.Offset))
+	return append(b, s.Hash...), nil
+}
+
+// UnmarshalBinary parses the binary encoding of a state and replaces the state.
+// The algorithm must be registered in the default [Registry].
+func (s *State) UnmarshalBinary(data []byte) error {
+	l, n := binary.Uvarint(data)
+	if n <= 0 || l > uint64(len(data)-n) {
+		return fmt.Errorf("%w: invalid binary encoding", ErrStateInvalid)
+	}
+	name := string(data[n : n+int(l)])
+	data = data[n+int(l):]
+	offset, n := binary.Uvarint(data)
+	if n <= 0 || offset > 1<<63-1 {
+		return fmt.Errorf("%w: invalid offset", ErrStateInvalid)
+	}
+	alg, err := AlgorithmLookup(name)
+	if err != nil {
+		return err
+	}
+	*s = State{
+		Algorithm: alg,
+		Offset:    int64(offset),
+		Hash:      append([]byte{}, data[n:]...),
+	}
+	return nil
+}
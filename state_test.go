@@ -0,0 +1,215 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"testing"
+)
+
+func TestWriterState(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	split := 4321
+	for _, alg := range []Algorithm{SHA256, SHA512} {
+		t.Run(alg.String(), func(t *testing.T) {
+			expect, err := alg.FromBytes(data)
+			if err != nil {
+				t.Fatalf("failed to digest: %v", err)
+			}
+			buf := &bytes.Buffer{}
+			w := NewWriter(buf, alg)
+			if _, err := w.Write(data[:split]); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+			s, err := w.State()
+			if err != nil {
+				t.Fatalf("failed to get state: %v", err)
+			}
+			if s.Offset != int64(split) || !s.Algorithm.Equal(alg) {
+				t.Errorf("unexpected state: %s, %d", s.Algorithm.String(), s.Offset)
+			}
+			// serialize and restore the state
+			b, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			var s2 State
+			if err := s2.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			w2, err := NewWriterState(buf, s2)
+			if err != nil {
+				t.Fatalf("failed to resume: %v", err)
+			}
+			if w2.BytesWritten() != int64(split) {
+				t.Errorf("expected %d bytes written after resume, received %d", split, w2.BytesWritten())
+			}
+			if _, err := w2.Write(data[split:]); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+			if !w2.Verify(expect) {
+				t.Errorf("digest mismatch after resume")
+			}
+			if w2.BytesWritten() != int64(len(data)) {
+				t.Errorf("expected %d bytes written, received %d", len(data), w2.BytesWritten())
+			}
+			if !bytes.Equal(buf.Bytes(), data) {
+				t.Errorf("pass through data mismatch")
+			}
+		})
+	}
+}
+
+func TestReaderState(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	split := 1234
+	expect, err := FromBytes(data)
+	if err != nil {
+		t.Fatalf("failed to digest: %v", err)
+	}
+	rs := bytes.NewReader(data)
+	r := NewReader(io.LimitReader(rs, int64(split)), Canonical)
+	if err := r.ReadAll(); err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	s, err := r.State()
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	if s.Offset != int64(split) {
+		t.Errorf("expected offset %d, received %d", split, s.Offset)
+	}
+	// resume with a new reader at the offset
+	rs = bytes.NewReader(data)
+	if _, err := rs.Seek(s.Offset, io.SeekStart); err != nil {
+		t.Fatalf("failed to seek: %v", err)
+	}
+	r2, err := NewReaderState(rs, s)
+	if err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	if err := r2.ReadAll(); err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if !r2.Verify(expect) {
+		t.Errorf("digest mismatch after resume")
+	}
+	if r2.BytesRead() != int64(len(data)) {
+		t.Errorf("expected %d bytes read, received %d", len(data), r2.BytesRead())
+	}
+}
+
+func TestStateErrors(t *testing.T) {
+	reg := NewRegistry()
+	algUnsupported, err := reg.Register("sha256-opaque", EncodeHex{Len: 64}, func() hash.Hash {
+		return opaqueHash{Hash: sha256.New()}
+	})
+	if err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+	t.Run("unsupported-marshal", func(t *testing.T) {
+		_, err := NewWriter(nil, algUnsupported).State()
+		if !errors.Is(err, ErrStateUnsupported) {
+			t.Errorf("expected unsupported error, received %v", err)
+		}
+		_, err = NewReader(bytes.NewReader(nil), algUnsupported).State()
+		if !errors.Is(err, ErrStateUnsupported) {
+			t.Errorf("expected unsupported error, received %v", err)
+		}
+	})
+	t.Run("unsupported-unmarshal", func(t *testing.T) {
+		_, err := NewWriterState(nil, State{Algorithm: algUnsupported})
+		if !errors.Is(err, ErrStateUnsupported) {
+			t.Errorf("expected unsupported error, received %v", err)
+		}
+		_, err = NewReaderState(nil, State{Algorithm: algUnsupported})
+		if !errors.Is(err, ErrStateUnsupported) {
+			t.Errorf("expected unsupported error, received %v", err)
+		}
+	})
+	t.Run("invalid-hash", func(t *testing.T) {
+		_, err := NewWriterState(nil, State{Algorithm: SHA256, Hash: []byte("invalid")})
+		if !errors.Is(err, ErrStateInvalid) {
+			t.Errorf("expected invalid error, received %v", err)
+		}
+		// sha512 state cannot be restored to sha256
+		s, err := NewWriter(nil, SHA512).State()
+		if err != nil {
+			t.Fatalf("failed to get state: %v", err)
+		}
+		s.Algorithm = SHA256
+		_, err = NewReaderState(nil, s)
+		if !errors.Is(err, ErrStateInvalid) {
+			t.Errorf("expected invalid error, received %v", err)
+		}
+	})
+	t.Run("invalid-offset", func(t *testing.T) {
+		s, err := NewWriter(nil, SHA256).State()
+		if err != nil {
+			t.Fatalf("failed to get state: %v", err)
+		}
+		s.Offset = -1
+		_, err = NewWriterState(nil, s)
+		if !errors.Is(err, ErrStateInvalid) {
+			t.Errorf("expected invalid error, received %v", err)
+		}
+		_, err = s.MarshalBinary()
+		if !errors.Is(err, ErrStateInvalid) {
+			t.Errorf("expected invalid error, received %v", err)
+		}
+	})
+	t.Run("unknown-algorithm", func(t *testing.T) {
+		_, err := NewWriterState(nil, State{})
+		if !errors.Is(err, ErrAlgorithmInvalidName) {
+			t.Errorf("expected invalid algorithm error, received %v", err)
+		}
+		b, err := State{Algorithm: Algorithm{name: "unknown"}}.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		var s State
+		if err := s.UnmarshalBinary(b); !errors.Is(err, ErrAlgorithmUnknown) {
+			t.Errorf("expected unknown algorithm error, received %v", err)
+		}
+	})
+	t.Run("invalid-binary", func(t *testing.T) {
+		var s State
+		for _, b := range [][]byte{nil, {0x10, 's'}, {0x06, 's', 'h', 'a', '2', '5', '6'}} {
+			if err := s.UnmarshalBinary(b); !errors.Is(err, ErrStateInvalid) {
+				t.Errorf("expected invalid error for %x, received %v", b, err)
+			}
+		}
+		if _, err := (State{}).MarshalBinary(); !errors.Is(err, ErrStateInvalid) {
+			t.Errorf("expected invalid error, received %v", err)
+		}
+	})
+	t.Run("invalid-reader-writer", func(t *testing.T) {
+		if _, err := (Reader{}).State(); !errors.Is(err, ErrReaderInvalid) {
+			t.Errorf("expected invalid reader, received %v", err)
+		}
+		if _, err := (Writer{}).State(); !errors.Is(err, ErrWriterInvalid) {
+			t.Errorf("expected invalid writer, received %v", err)
+		}
+	})
+}
+
+// opaqueHash hides the marshal methods of the wrapped hash.
+type opaqueHash struct {
+	hash.Hash
+}
@@ -0,0 +1,150 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+)
+
+// Verifier is a [Reader] that verifies the content matches an expected [Digest].
+// The verification is performed when the underlying reader returns [io.EOF].
+// An optional size limit is enforced on every read.
+type Verifier struct {
+	r        Reader
+	expect   Digest
+	size     int64
+	read     int64
+	exceeded bool
+}
+
+// NewVerifier creates a [Verifier].
+// The algorithm used to compute the digest is taken from the expected digest.
+// When the underlying reader returns [io.EOF], a [MismatchError] is returned instead if the content does not match.
+func NewVerifier(r io.Reader, expect Digest) *Verifier {
+	return &Verifier{
+		r:      NewReader(r, expect.Algorithm()),
+		expect: expect,
+		size:   -1,
+	}
+}
+
+// NewVerifierSize creates a [Verifier] that also enforces the size of the content.
+// Reading more than size bytes immediately returns [ErrSizeExceeded].
+// Reaching [io.EOF] before reading size bytes returns [ErrSizeShort].
+// The digest is only verified after the size has been verified.
+// A negative size disables the size check.
+func NewVerifierSize(r io.Reader, expect Digest, size int64) *Verifier {
+	v := NewVerifier(r, expect)
+	if size >= 0 {
+		v.size = size
+	}
+	return v
+}
+
+// Digest returns the current digest value.
+func (v *Verifier) Digest() (Digest, error) {
+	return v.r.Digest()
+}
+
+// Expected returns the expected digest.
+func (v *Verifier) Expected() Digest {
+	return v.expect
+}
+
+// Hash returns the underlying [hash.Hash].
+// Direct writes to this hash will affect the returned digest.
+func (v *Verifier) Hash() hash.Hash {
+	return v.r.Hash()
+}
+
+// Read will pass through the read requests to the underlying reader.
+// All read data is included in the digest computation.
+// When the underlying reader returns [io.EOF], the digest is verified and a [MismatchError] is returned on a mismatch.
+// If a size was provided, [ErrSizeExceeded] is returned as soon as more than that many bytes are read,
+// and the digest only includes the bytes up to the size.
+func (v *Verifier) Read(p []byte) (int, error) {
+	if v.r.r == nil {
+		return 0, ErrReaderInvalid
+	}
+	buf := p
+	if v.size >= 0 {
+		if v.exceeded {
+			return 0, fmt.Errorf("%w: expected %d bytes", ErrSizeExceeded, v.size)
+		}
+		// allow one extra byte to detect content exceeding the size
+		remain := v.size - v.read
+		if remain < math.MaxInt64 {
+			remain++
+		}
+		if int64(len(buf)) > remain {
+			buf = buf[:remain]
+		}
+	}
+	n, err := v.r.r.Read(buf)
+	if n < 0 {
+		// ignore an invalid count from the underlying reader rather than panic on the slice
+		n = 0
+	}
+	if v.size >= 0 && int64(n) > v.size-v.read {
+		n = int(v.size - v.read)
+		v.read = v.size
+		v.exceeded = true
+		sErr := fmt.Errorf("%w: expected %d bytes", ErrSizeExceeded, v.size)
+		if hErr := v.r.hashRead(p[:n], nil); hErr != nil {
+			return n, errors.Join(sErr, hErr)
+		}
+		return n, sErr
+	}
+	v.read += int64(n)
+	err = v.r.hashRead(p[:n], err)
+	if errors.Is(err, io.EOF) {
+		if vErr := v.verify(); vErr != nil {
+			return n, vErr
+		}
+	}
+	return n, err
+}
+
+// ReadAll reads everything from the underlying reader, computing the digest, and then discarding the read value.
+// A [MismatchError] is returned if the content does not match the expected digest.
+func (v *Verifier) ReadAll() error {
+	_, err := io.Copy(io.Discard, v)
+	return err
+}
+
+// Verify returns true if the current digest matches the expected digest.
+// The comparison is performed with [Digest.EqualConstantTime].
+// Any errors in computing the digest will also return false.
+func (v *Verifier) Verify() bool {
+	return v.r.VerifyConstantTime(v.expect)
+}
+
+func (v *Verifier) verify() error {
+	if v.size >= 0 && v.read < v.size {
+		return fmt.Errorf("%w: expected %d bytes, received %d", ErrSizeShort, v.size, v.read)
+	}
+	d, err := v.r.Digest()
+	if err != nil {
+		return err
+	}
+	if v.expect.IsZero() || !d.EqualConstantTime(v.expect) {
+		return &MismatchError{Expected: v.expect, Actual: d}
+	}
+	return nil
+}
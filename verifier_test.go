@@ -0,0 +1,225 @@
+// Copyright the oci-digest contributors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package digest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestVerifier(t *testing.T) {
+	emptyJSON := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	emptyJSON512 := Digest{alg: "sha512", enc: "27c74670adb75075fad058d5ceaf7b20c4e7786c83bae8a32f626f9782af34c9a33c2046ef60fd2a7878d378e29fec851806bbd9a67878f3a9f1cda4830763fd"}
+	empty := Digest{alg: "sha256", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	tt := []struct {
+		name   string
+		v      *Verifier
+		bytes  []byte
+		expect Digest
+		actual Digest
+		err    error
+	}{
+		{
+			name: "nil",
+			v:    &Verifier{},
+			err:  ErrReaderInvalid,
+		},
+		{
+			name:   "sha256-match",
+			v:      NewVerifier(bytes.NewReader([]byte("{}")), emptyJSON),
+			bytes:  []byte("{}"),
+			expect: emptyJSON,
+			actual: emptyJSON,
+		},
+		{
+			name:   "sha512-match",
+			v:      NewVerifier(bytes.NewReader([]byte("{}")), emptyJSON512),
+			bytes:  []byte("{}"),
+			expect: emptyJSON512,
+			actual: emptyJSON512,
+		},
+		{
+			name:   "sha256-mismatch",
+			v:      NewVerifier(bytes.NewReader([]byte("{}")), empty),
+			bytes:  []byte("{}"),
+			expect: empty,
+			actual: emptyJSON,
+			err:    ErrDigestMismatch,
+		},
+		{
+			name:   "zero-expected",
+			v:      NewVerifier(bytes.NewReader([]byte("{}")), Digest{}),
+			bytes:  []byte("{}"),
+			actual: emptyJSON,
+			err:    ErrDigestMismatch,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := io.ReadAll(tc.v)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !bytes.Equal(out, tc.bytes) {
+				t.Errorf("expected bytes %s, received %s", tc.bytes, out)
+			}
+			if errors.Is(tc.err, ErrDigestMismatch) {
+				var mErr *MismatchError
+				if !errors.As(err, &mErr) {
+					t.Fatalf("expected a MismatchError, received %v", err)
+				}
+				if !mErr.Expected.Equal(tc.expect) {
+					t.Errorf("expected digest %s, received %s", tc.expect.String(), mErr.Expected.String())
+				}
+				if !mErr.Actual.Equal(tc.actual) {
+					t.Errorf("actual digest %s, received %s", tc.actual.String(), mErr.Actual.String())
+				}
+			}
+			if tc.err == nil && !tc.v.Verify() {
+				t.Errorf("verify failed")
+			}
+			if tc.err != nil && tc.v.Verify() {
+				t.Errorf("unexpected verify")
+			}
+		})
+	}
+}
+
+func TestVerifierReadAll(t *testing.T) {
+	match := NewVerifier(bytes.NewReader([]byte("{}")), Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"})
+	if err := match.ReadAll(); err != nil {
+		t.Errorf("unexpected err: %v", err)
+	}
+	mismatch := NewVerifier(bytes.NewReader([]byte("{}")), Digest{alg: "sha256", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"})
+	if err := mismatch.ReadAll(); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("expected err %v, received %v", ErrDigestMismatch, err)
+	}
+}
+
+func TestVerifierSize(t *testing.T) {
+	emptyJSON := Digest{alg: "sha256", enc: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}
+	empty := Digest{alg: "sha256", enc: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	tt := []struct {
+		name   string
+		in     []byte
+		expect Digest
+		size   int64
+		out    []byte
+		err    error
+	}{
+		{
+			name:   "match",
+			in:     []byte("{}"),
+			expect: emptyJSON,
+			size:   2,
+			out:    []byte("{}"),
+		},
+		{
+			name:   "unbounded",
+			in:     []byte("{}"),
+			expect: emptyJSON,
+			size:   -1,
+			out:    []byte("{}"),
+		},
+		{
+			name:   "empty",
+			in:     []byte{},
+			expect: empty,
+			size:   0,
+			out:    []byte{},
+		},
+		{
+			name:   "too-long",
+			in:     []byte("{}"),
+			expect: emptyJSON,
+			size:   1,
+			out:    []byte("{"),
+			err:    ErrSizeExceeded,
+		},
+		{
+			name:   "too-short",
+			in:     []byte("{}"),
+			expect: emptyJSON,
+			size:   3,
+			out:    []byte("{}"),
+			err:    ErrSizeShort,
+		},
+		{
+			name:   "max-size",
+			in:     []byte("{}"),
+			expect: emptyJSON,
+			size:   math.MaxInt64,
+			out:    []byte("{}"),
+			err:    ErrSizeShort,
+		},
+		{
+			name:   "mismatch",
+			in:     []byte("{}"),
+			expect: empty,
+			size:   2,
+			out:    []byte("{}"),
+			err:    ErrDigestMismatch,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewVerifierSize(bytes.NewReader(tc.in), tc.expect, tc.size)
+			out, err := io.ReadAll(v)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected err %v, received %v", tc.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if !bytes.Equal(out, tc.out) {
+				t.Errorf("expected bytes %s, received %s", tc.out, out)
+			}
+			// the digest only includes the returned bytes
+			d, err := v.Digest()
+			if err != nil {
+				t.Fatalf("failed to get digest: %v", err)
+			}
+			expect, err := FromBytes(tc.out)
+			if err != nil {
+				t.Fatalf("failed to digest: %v", err)
+			}
+			if !d.Equal(expect) {
+				t.Errorf("expected digest %s, received %s", expect.String(), d.String())
+			}
+			// errors are repeated on subsequent reads
+			if tc.err != nil {
+				if _, err := v.Read(make([]byte, 10)); !errors.Is(err, tc.err) {
+					t.Errorf("expected repeated err %v, received %v", tc.err, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifierNegativeCount(t *testing.T) {
+	v := NewVerifierSize(negativeReader{}, Digest{}, 5)
+	n, err := v.Read(make([]byte, 10))
+	if n != 0 || !errors.Is(err, io.ErrNoProgress) {
+		t.Errorf("expected 0 and the reader error, received %d, %v", n, err)
+	}
+}
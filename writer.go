@@ -23,20 +23,50 @@ import (
 // Writer is used to calculate the digest with a writer.
 // It will pass through calls to a [io.Writer] if one is provided.
 type Writer struct {
-	w    io.Writer
-	alg  Algorithm
-	hash hash.Hash
+	w     io.Writer
+	alg   Algorithm
+	hash  hash.Hash
+	count *counter
 }
 
 // NewWriter creates a [Writer].
 // If the Writer is provided, write calls are passed through while digesting.
 // If Algorithm is the zero value, the [Canonical] value will be used.
 func NewWriter(w io.Writer, alg Algorithm) Writer {
+	return newWriter(w, alg, &counter{})
+}
+
+// NewWriterProgress creates a [Writer] that reports the cumulative bytes written to the [ProgressFunc].
+// The func is called each time at least interval bytes have been written since the previous call.
+// Call [Writer.Flush] after the last write to report the final total.
+// An interval <= 0 calls the func after every write.
+func NewWriterProgress(w io.Writer, alg Algorithm, interval int64, fn ProgressFunc) Writer {
+	return newWriter(w, alg, &counter{interval: interval, fn: fn})
+}
+
+// NewWriterState creates a [Writer] that resumes the digest from a [State] returned by [Writer.State].
+// The writer must be positioned at the state offset, e.g. by opening the underlying file for append, and [Writer.BytesWritten] starts at the offset.
+// This will fail if the algorithm is not registered or the hash state cannot be restored.
+func NewWriterState(w io.Writer, s State) (Writer, error) {
+	h, count, err := s.restore()
+	if err != nil {
+		return Writer{}, err
+	}
+	return Writer{
+		w:     w,
+		alg:   s.Algorithm,
+		hash:  h,
+		count: count,
+	}, nil
+}
+
+func newWriter(w io.Writer, alg Algorithm, count *counter) Writer {
 	ret := Writer{
-		w:   w,
-		alg: alg,
+		w:     w,
+		alg:   alg,
+		count: count,
 	}
-	ai, _, err := algorithmInfoLookup(alg.name)
+	ai, err := alg.info()
 	if err != nil {
 		ret.alg = Canonical
 		ai = aiCanonical
@@ -45,6 +75,12 @@ func NewWriter(w io.Writer, alg Algorithm) Writer {
 	return ret
 }
 
+// BytesWritten returns the number of bytes written and added to the digest.
+// Direct writes to the [hash.Hash] are not included.
+func (w Writer) BytesWritten() int64 {
+	return w.count.load()
+}
+
 // Digest returns the digest for the bytes that have received by Write.
 func (w Writer) Digest() (Digest, error) {
 	if w.hash == nil {
@@ -53,12 +89,27 @@ func (w Writer) Digest() (Digest, error) {
 	return NewDigest(w.alg, w.hash)
 }
 
+// Flush reports the total bytes written to the [ProgressFunc] if it has not already been reported.
+// This should be called after the last write, since the final partial interval is otherwise not reported.
+func (w Writer) Flush() {
+	w.count.flush()
+}
+
 // Hash returns the underlying [hash.Hash].
 // Direct writes to this hash will affect the returned digest.
 func (w Writer) Hash() hash.Hash {
 	return w.hash
 }
 
+// State returns the serializable state of the digest, used to resume with [NewWriterState].
+// This will fail with [ErrStateUnsupported] if the hash for the algorithm does not implement [encoding.BinaryMarshaler].
+func (w Writer) State() (State, error) {
+	if w.hash == nil {
+		return State{}, ErrWriterInvalid
+	}
+	return newState(w.alg, w.hash, w.count.load())
+}
+
 // Verify returns true if the compared digest matches the current digest.
 // Any errors in computing the digest will also return false.
 func (w Writer) Verify(cmp Digest) bool {
@@ -69,6 +120,17 @@ func (w Writer) Verify(cmp Digest) bool {
 	return !cmp.IsZero() && d.Equal(cmp)
 }
 
+// VerifyConstantTime returns true if the compared digest matches the current digest.
+// The comparison is performed with [Digest.EqualConstantTime].
+// Any errors in computing the digest will also return false.
+func (w Writer) VerifyConstantTime(cmp Digest) bool {
+	d, err := w.Digest()
+	if err != nil {
+		return false
+	}
+	return !cmp.IsZero() && d.EqualConstantTime(cmp)
+}
+
 // Write passes through the bytes to the underlying writer if provided.
 // The processed bytes are then added to the digest.
 func (w Writer) Write(p []byte) (n int, err error) {
@@ -84,6 +146,7 @@ func (w Writer) Write(p []byte) (n int, err error) {
 		return n, err
 	}
 	_, hErr := w.hash.Write(p[:n])
+	w.count.add(int64(n))
 	if hErr != nil {
 		if err != nil {
 			err = errors.Join(err, hErr)
@@ -121,6 +121,12 @@ func TestWriter(t *testing.T) {
 			if tc.errDigest == nil && !tc.w.Verify(tc.expect) {
 				t.Errorf("verify failed")
 			}
+			if tc.w.VerifyConstantTime(tc.mismatch) {
+				t.Errorf("unexpected constant time verify of mismatch")
+			}
+			if tc.errDigest == nil && !tc.w.VerifyConstantTime(tc.expect) {
+				t.Errorf("constant time verify failed")
+			}
 			// access hash directly
 			if tc.errDigest == nil {
 				h := tc.w.Hash()